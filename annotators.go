@@ -1,6 +1,9 @@
 package fail
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 // Annotator is a function that annotates an error with information
 type Annotator func(*Error)
@@ -27,17 +30,17 @@ func WithCode(code interface{}) Annotator {
 	}
 }
 
-// WithIgnorable annotates an error with the reportability
+// WithIgnorable annotates an error with the reportability.
+// It's the same as WithSeverityOverride(SeverityInfo).
 func WithIgnorable() Annotator {
-	return func(err *Error) {
-		err.Ignorable = true
-	}
+	return WithSeverityOverride(SeverityInfo)
 }
 
-// WithTags annotates an error with tags
+// WithTags annotates an error with tags.
+// Tags that the error already has are not added again.
 func WithTags(tags ...string) Annotator {
 	return func(err *Error) {
-		err.Tags = append(err.Tags, tags...)
+		err.Tags = TagSet(err.Tags).Add(tags...)
 	}
 }
 
@@ -46,13 +49,30 @@ func WithParam(key string, value interface{}) Annotator {
 	return WithParams(H{key: value})
 }
 
-// WithParams annotates an error with key-value pairs
+// WithParams annotates an error with key-value pairs.
+// Values of the same keys are replaced, including nested objects.
 func WithParams(h H) Annotator {
 	return func(err *Error) {
 		err.Params = err.Params.Merge(h)
 	}
 }
 
+// WithParamsStrategy annotates an error with key-value pairs,
+// merging values of the same keys and nested objects by the strategy
+func WithParamsStrategy(h H, s MergeStrategy) Annotator {
+	return func(err *Error) {
+		err.Params = err.Params.MergeWith(h, s)
+	}
+}
+
+// WithParamPath annotates an error with a value at the dotted path of nested params, such as "user.id".
+// Other values in the nested objects on the path are kept.
+func WithParamPath(path string, value interface{}) Annotator {
+	return func(err *Error) {
+		err.Params = err.Params.mergeDeep(nest(strings.Split(path, pathSeparator), value), MergeOuterWins)
+	}
+}
+
 // withStackTrace annotates an error with the stack trace from the point it was called
 func withStackTrace(offset int) Annotator {
 	stackTrace := newStackTrace(offset + 1)
@@ -0,0 +1,117 @@
+package fail
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"sync"
+	"syscall"
+)
+
+// Tags annotated by the built-in classification
+const (
+	TagFS  = "fs"
+	TagNet = "net"
+)
+
+// Classifier returns an annotator for the error, or nil if it doesn't know the error.
+// It's used to infer the code, the retryability and tags of errors from other packages.
+type Classifier func(err error) Annotator
+
+var (
+	classifiersMu sync.RWMutex
+	classifiers   []Classifier
+)
+
+// RegisterClassifier registers a classifier.
+// Classifiers are consulted in the order they were registered, and before the built-in classification
+// of errors of os, io/fs, net, syscall and context.
+func RegisterClassifier(c Classifier) {
+	classifiersMu.Lock()
+	defer classifiersMu.Unlock()
+	classifiers = append(classifiers, c)
+}
+
+// Classify returns an annotator for the error from the registered classifiers
+// or the built-in classification, or nil if the error is unknown.
+func Classify(err error) Annotator {
+	if err == nil {
+		return nil
+	}
+
+	classifiersMu.RLock()
+	cs := classifiers
+	classifiersMu.RUnlock()
+
+	for _, c := range cs {
+		if f := c(err); f != nil {
+			return f
+		}
+	}
+	return classifyStd(err)
+}
+
+// WithClassification annotates an error with the classification of its root error by Classify.
+// Register it with OnCreate to classify every error wrapped with Wrap,
+// where the annotators passed to Wrap override the classification.
+//
+//	fail.OnCreate(fail.WithClassification())
+func WithClassification() Annotator {
+	return func(err *Error) {
+		if f := Classify(err.Err); f != nil {
+			f(err)
+		}
+	}
+}
+
+// classifyStd classifies errors of the standard library
+func classifyStd(err error) Annotator {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return classified(CodeDeadlineExceeded, true)
+	case errors.Is(err, context.Canceled):
+		return classified(CodeCanceled, false)
+	case errors.Is(err, os.ErrNotExist):
+		return classified(CodeNotFound, false, TagFS)
+	case errors.Is(err, os.ErrExist):
+		return classified(CodeAlreadyExists, false, TagFS)
+	case errors.Is(err, os.ErrPermission):
+		return classified(CodePermissionDenied, false, TagFS)
+	case errors.Is(err, syscall.ECONNREFUSED),
+		errors.Is(err, syscall.ECONNRESET),
+		errors.Is(err, syscall.ECONNABORTED),
+		errors.Is(err, syscall.EHOSTUNREACH),
+		errors.Is(err, syscall.ENETUNREACH):
+		return classified(CodeUnavailable, true, TagNet)
+	}
+
+	// syscall.Errno also implements net.Error, so errors of the file system are excluded
+	var netErr net.Error
+	if errors.As(err, &netErr) && !isErrno(netErr) {
+		if netErr.Timeout() {
+			return classified(CodeDeadlineExceeded, true, TagNet)
+		}
+		return classified(CodeUnavailable, netErr.Temporary(), TagNet)
+	}
+	return nil
+}
+
+// isErrno reports whether the error is syscall.Errno
+func isErrno(err error) bool {
+	_, ok := err.(syscall.Errno)
+	return ok
+}
+
+// classified returns an annotator of the code, the retryability and the tags
+func classified(code CanonicalCode, retryable bool, tags ...string) Annotator {
+	return func(err *Error) {
+		WithCode(code)(err)
+		if retryable {
+			WithRetryable()(err)
+		}
+		if len(tags) > 0 {
+			WithTags(tags...)(err)
+		}
+	}
+}
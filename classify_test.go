@@ -0,0 +1,101 @@
+package fail
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// restoreClassifiers returns a function to restore the registered classifiers
+func restoreClassifiers() func() {
+	classifiersMu.RLock()
+	original := classifiers
+	classifiersMu.RUnlock()
+
+	return func() {
+		classifiersMu.Lock()
+		defer classifiersMu.Unlock()
+		classifiers = original
+	}
+}
+
+func TestClassify(t *testing.T) {
+	_, notExistErr := os.Open("/path/to/not/exist")
+	_, isDirErr := os.OpenFile(os.TempDir(), os.O_WRONLY, 0)
+
+	cases := []struct {
+		test          string
+		err           error
+		wantCode      interface{}
+		wantRetryable bool
+		wantTags      []string
+	}{
+		{test: "unknown", err: errors.New("origin")},
+		{test: "context.DeadlineExceeded", err: context.DeadlineExceeded, wantCode: CodeDeadlineExceeded, wantRetryable: true},
+		{test: "context.Canceled", err: fmt.Errorf("query: %w", context.Canceled), wantCode: CodeCanceled},
+		{test: "os.ErrNotExist", err: notExistErr, wantCode: CodeNotFound, wantTags: []string{"fs"}},
+		{test: "os.ErrExist", err: os.ErrExist, wantCode: CodeAlreadyExists, wantTags: []string{"fs"}},
+		{test: "os.ErrPermission", err: &os.PathError{Op: "open", Path: "/root", Err: syscall.EACCES}, wantCode: CodePermissionDenied, wantTags: []string{"fs"}},
+		{test: "ECONNREFUSED", err: &net.OpError{Op: "dial", Net: "tcp", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)}, wantCode: CodeUnavailable, wantRetryable: true, wantTags: []string{"net"}},
+		{test: "net timeout", err: &net.OpError{Op: "read", Net: "tcp", Err: &netError{timeout: true}}, wantCode: CodeDeadlineExceeded, wantRetryable: true, wantTags: []string{"net"}},
+		{test: "net permanent", err: &netError{}, wantCode: CodeUnavailable, wantTags: []string{"net"}},
+		{test: "EISDIR", err: isDirErr},
+		{test: "errno", err: syscall.ETIMEDOUT},
+	}
+
+	for _, c := range cases {
+		t.Run(c.test, func(t *testing.T) {
+			err := Unwrap(Wrap(c.err, WithClassification()))
+
+			assert.Equal(t, c.wantCode, err.Code)
+			assert.Equal(t, c.wantRetryable, err.Retryable)
+			assert.Equal(t, c.wantTags, err.Tags)
+		})
+	}
+
+	t.Run("nil", func(t *testing.T) {
+		assert.Nil(t, Classify(nil))
+	})
+}
+
+func TestRegisterClassifier(t *testing.T) {
+	defer restoreClassifiers()()
+
+	errCustom := errors.New("custom")
+	RegisterClassifier(func(err error) Annotator {
+		if errors.Is(err, errCustom) || errors.Is(err, context.Canceled) {
+			return WithCode(499)
+		}
+		return nil
+	})
+
+	assert.Equal(t, 499, Unwrap(Wrap(errCustom, WithClassification())).Code)
+	assert.Equal(t, 499, Unwrap(Wrap(context.Canceled, WithClassification())).Code)
+	assert.Equal(t, CodeDeadlineExceeded, Unwrap(Wrap(context.DeadlineExceeded, WithClassification())).Code)
+}
+
+func TestWithClassification_OnCreate(t *testing.T) {
+	defer restoreHooks()()
+	OnCreate(WithClassification())
+
+	t.Run("classified", func(t *testing.T) {
+		err := Unwrap(Wrap(os.ErrNotExist))
+		assert.Equal(t, CodeNotFound, err.Code)
+	})
+
+	t.Run("overridden by annotators", func(t *testing.T) {
+		err := Unwrap(Wrap(os.ErrNotExist, WithCode(410)))
+		assert.Equal(t, 410, err.Code)
+	})
+
+	t.Run("only on creation", func(t *testing.T) {
+		err := Unwrap(Wrap(Wrap(os.ErrNotExist, WithCode(410))))
+		assert.Equal(t, 410, err.Code)
+	})
+}
@@ -0,0 +1,26 @@
+package fail
+
+import (
+	"sync"
+	"time"
+)
+
+var (
+	clockMu sync.RWMutex
+	clock   = time.Now
+)
+
+// SetClock sets the function that returns the current time for CreatedAt and WrappedAt.
+// It's time.Now by default, and is intended to be replaced in tests.
+func SetClock(now func() time.Time) {
+	clockMu.Lock()
+	defer clockMu.Unlock()
+	clock = now
+}
+
+// now returns the current time from the clock set by SetClock
+func now() time.Time {
+	clockMu.RLock()
+	defer clockMu.RUnlock()
+	return clock()
+}
@@ -0,0 +1,75 @@
+package fail
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// restoreClock sets a clock that advances by a second on every call,
+// and returns a function to restore the original clock
+func restoreClock() func() {
+	clockMu.RLock()
+	original := clock
+	clockMu.RUnlock()
+
+	t := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	SetClock(func() time.Time {
+		t = t.Add(time.Second)
+		return t
+	})
+
+	return func() { SetClock(original) }
+}
+
+func at(sec int) time.Time {
+	return time.Date(2020, 1, 1, 0, 0, sec, 0, time.UTC)
+}
+
+func TestError_Timestamps(t *testing.T) {
+	t.Run("New", func(t *testing.T) {
+		defer restoreClock()()
+
+		err := Unwrap(New("origin"))
+		assert.Equal(t, at(1), err.CreatedAt)
+		assert.True(t, err.WrappedAt.IsZero())
+	})
+
+	t.Run("Errorf", func(t *testing.T) {
+		defer restoreClock()()
+
+		err := Unwrap(Errorf("origin %d", 1))
+		assert.Equal(t, at(1), err.CreatedAt)
+		assert.True(t, err.WrappedAt.IsZero())
+	})
+
+	t.Run("Kind", func(t *testing.T) {
+		defer restoreClock()()
+
+		kind := Define("internal")
+		assert.Equal(t, at(1), Unwrap(kind.New("origin")).CreatedAt)
+		assert.Equal(t, at(2), Unwrap(kind.Errorf("origin")).CreatedAt)
+	})
+
+	t.Run("Wrap", func(t *testing.T) {
+		defer restoreClock()()
+
+		err0 := New("origin")
+		err1 := Wrap(err0)
+		err2 := Wrap(err1)
+
+		assert.Equal(t, at(1), Unwrap(err2).CreatedAt)
+		assert.Equal(t, at(2), Unwrap(err1).WrappedAt)
+		assert.Equal(t, at(3), Unwrap(err2).WrappedAt)
+	})
+
+	t.Run("Wrap an external error", func(t *testing.T) {
+		defer restoreClock()()
+
+		err := Unwrap(Wrap(errors.New("origin")))
+		assert.Equal(t, at(1), err.CreatedAt)
+		assert.Equal(t, at(1), err.WrappedAt)
+	})
+}
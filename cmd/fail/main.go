@@ -0,0 +1,161 @@
+// Command fail parses errors of fail from logs and pretty-prints them.
+//
+// It reads JSON log records that contain a serialized *fail.Error, "%+v" outputs
+// of *fail.Error, or "%+v" outputs of pkg/errors from files or stdin.
+// Frames outside of the application are collapsed, where the application is
+// the module in the current directory unless -app is given.
+//
+// Usage:
+//
+//	kubectl logs deploy/app | fail -group
+//	fail -source 3 app.log
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/srvc/fail/v4"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stdin, os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "fail: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdin io.Reader, stdout io.Writer) error {
+	fs := flag.NewFlagSet("fail", flag.ContinueOnError)
+	color := fs.String("color", "auto", "colorize the output: auto, always or never")
+	app := fs.String("app", "", "comma-separated prefixes of files or functions in the application (default: the module path of go.mod in the current directory)")
+	all := fs.Bool("all", false, "show all frames without collapsing frames outside of the application")
+	group := fs.Bool("group", false, "group errors by fingerprints with counts")
+	source := fs.Int("source", 0, "number of source lines shown around frames in the application")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	useColor, err := detectColor(*color, stdout)
+	if err != nil {
+		return err
+	}
+
+	modulePath := readModulePath("go.mod")
+	p := &Printer{
+		Out:   stdout,
+		Color: useColor,
+		All:   *all,
+	}
+	if *app != "" {
+		p.InAppPrefixes = strings.Split(*app, ",")
+	} else if modulePath != "" {
+		p.InAppPrefixes = []string{modulePath}
+	}
+	if *source > 0 {
+		p.Source = fail.NewSourceLoader(*source, nil).WithModules(p.InAppPrefixes...)
+	}
+
+	errs, err := readErrors(fs.Args(), stdin)
+	if err != nil {
+		return err
+	}
+
+	if *group {
+		groups := GroupErrors(errs)
+		sort.SliceStable(groups, func(i, j int) bool { return groups[i].Count > groups[j].Count })
+		for _, g := range groups {
+			p.PrintGroup(g)
+		}
+		return nil
+	}
+
+	for _, err := range errs {
+		p.Print(err)
+	}
+	return nil
+}
+
+// readErrors parses errors from the files, or stdin if no files are given
+func readErrors(paths []string, stdin io.Reader) ([]*fail.Error, error) {
+	if len(paths) == 0 {
+		return Parse(stdin)
+	}
+
+	var errs []*fail.Error
+	for _, path := range paths {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		parsed, err := Parse(f)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %v", path, err)
+		}
+		errs = append(errs, parsed...)
+	}
+	return errs, nil
+}
+
+// detectColor reports whether the output should be colorized
+func detectColor(mode string, out io.Writer) (bool, error) {
+	switch mode {
+	case "always":
+		return true, nil
+	case "never":
+		return false, nil
+	case "auto":
+		if _, ok := os.LookupEnv("NO_COLOR"); ok {
+			return false, nil
+		}
+		f, ok := out.(*os.File)
+		if !ok {
+			return false, nil
+		}
+		info, err := f.Stat()
+		return err == nil && info.Mode()&os.ModeCharDevice != 0, nil
+	default:
+		return false, fmt.Errorf("invalid -color %q: must be auto, always or never", mode)
+	}
+}
+
+var moduleRegexp = regexp.MustCompile(`(?m)^module\s+"?([^\s"]+)"?`)
+
+// readModulePath returns the module path of the go.mod, or "" if it's not found
+func readModulePath(path string) string {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	if m := moduleRegexp.FindSubmatch(data); m != nil {
+		return string(m[1])
+	}
+	return ""
+}
+
+// sortedKeys returns the keys of the map in sorted order
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// sortedLabelKeys returns the keys of the labels in sorted order
+func sortedLabelKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,232 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/srvc/fail/v4"
+)
+
+// Parse reads errors from the reader.
+//
+// Each line is either a JSON log record that contains a serialized *fail.Error,
+// possibly nested in another object, or a part of a text block.
+// Text blocks are separated by blank lines or JSON lines, and each block is the "%+v" output
+// of a *fail.Error or an error of pkg/errors.
+func Parse(r io.Reader) ([]*fail.Error, error) {
+	var errs []*fail.Error
+	var block []string
+
+	flush := func() {
+		if err := parseText(block); err != nil {
+			errs = append(errs, err)
+		}
+		block = block[:0]
+	}
+
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for sc.Scan() {
+		line := strings.TrimRight(sc.Text(), "\r")
+
+		if strings.HasPrefix(strings.TrimSpace(line), "{") {
+			if err, ok := parseJSON([]byte(line)); ok {
+				flush()
+				if err != nil {
+					errs = append(errs, err)
+				}
+				continue
+			}
+		}
+		if strings.TrimSpace(line) == "" {
+			flush()
+			continue
+		}
+		block = append(block, line)
+	}
+	flush()
+
+	return errs, sc.Err()
+}
+
+// parseJSON parses a JSON log record.
+// It returns false if the line is not JSON, and nil if the record doesn't contain a serialized error.
+func parseJSON(line []byte) (*fail.Error, bool) {
+	var record interface{}
+	dec := json.NewDecoder(bytes.NewReader(line))
+	dec.UseNumber()
+	if err := dec.Decode(&record); err != nil {
+		return nil, false
+	}
+
+	obj := findErrorObject(record)
+	if obj == nil {
+		return nil, true
+	}
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, true
+	}
+	var failErr fail.Error
+	if err := json.Unmarshal(data, &failErr); err != nil {
+		return nil, true
+	}
+	return &failErr, true
+}
+
+// findErrorObject searches an object that looks like a serialized *fail.Error in breadth-first order
+func findErrorObject(v interface{}) map[string]interface{} {
+	queue := []interface{}{v}
+	for len(queue) > 0 {
+		obj, ok := queue[0].(map[string]interface{})
+		queue = queue[1:]
+		if !ok {
+			continue
+		}
+
+		if _, ok := obj["stack_trace"].([]interface{}); ok {
+			if _, ok := obj["error"].(string); ok {
+				return obj
+			}
+		}
+		for _, k := range sortedKeys(obj) {
+			queue = append(queue, obj[k])
+		}
+	}
+	return nil
+}
+
+// parseText parses a text block.
+//
+// The first line is the message of the root error, and the following lines are
+// metadata indented by 4 spaces, frames of a function name and a tab-indented "file:line",
+// or messages added by pkg/errors.
+// It returns nil if the block has no frames, since it's unlikely to be an error then.
+func parseText(lines []string) *fail.Error {
+	if len(lines) == 0 {
+		return nil
+	}
+
+	failErr := &fail.Error{Err: &fail.DecodedError{Message: lines[0]}}
+	stackDone := false
+	var remote bool
+	var origin fail.Origin
+
+	for i := 1; i < len(lines); i++ {
+		line := lines[i]
+
+		switch {
+		case strings.HasPrefix(line, "    "):
+			parseMetadata(failErr, strings.TrimSpace(line))
+		case strings.HasPrefix(line, "--- ") && strings.HasSuffix(line, " ---"):
+			remote, origin = parseSeparator(line)
+		case i+1 < len(lines) && strings.HasPrefix(lines[i+1], "\t"):
+			if !stackDone {
+				f := parseFrame(line, lines[i+1])
+				f.Remote, f.Origin = remote, origin
+				failErr.StackTrace = append(failErr.StackTrace, f)
+			}
+			i++
+		case strings.HasPrefix(line, "\t"):
+			// a file line without a function line
+		default:
+			// pkg/errors prints messages from innermost to outermost after each stack trace
+			failErr.Messages = append([]string{line}, failErr.Messages...)
+			stackDone = len(failErr.StackTrace) > 0
+		}
+	}
+
+	if len(failErr.StackTrace) == 0 {
+		return nil
+	}
+	return failErr
+}
+
+// parseSeparator parses a separator of frames of another origin, such as "--- remote (users) ---" and "--- local ---".
+// The name in parentheses is parsed as the service, since it can't be distinguished from the host.
+func parseSeparator(line string) (bool, fail.Origin) {
+	label := strings.TrimSuffix(strings.TrimPrefix(line, "--- "), " ---")
+	if !strings.HasPrefix(label, "remote") {
+		return false, fail.Origin{}
+	}
+	label = strings.TrimSpace(strings.TrimPrefix(label, "remote"))
+	return true, fail.Origin{Service: strings.TrimSuffix(strings.TrimPrefix(label, "("), ")")}
+}
+
+// parseFrame parses a function line and a "\tfile:line" line.
+// The offset of runtime/debug.Stack, such as " +0x1f", is removed.
+func parseFrame(funcLine, fileLine string) fail.Frame {
+	f := fail.Frame{Func: strings.TrimSpace(funcLine)}
+
+	loc := strings.TrimSpace(fileLine)
+	if i := strings.LastIndex(loc, " +0x"); i >= 0 {
+		loc = loc[:i]
+	}
+	if i := strings.LastIndex(loc, ":"); i >= 0 {
+		if n, err := strconv.ParseInt(loc[i+1:], 10, 64); err == nil {
+			f.File, f.Line = loc[:i], n
+			return f
+		}
+	}
+	f.File = loc
+	return f
+}
+
+// parseMetadata parses a "key: value" line written by the "%+v" format of *fail.Error
+func parseMetadata(failErr *fail.Error, line string) {
+	i := strings.Index(line, ": ")
+	if i < 0 {
+		return
+	}
+	key, value := line[:i], line[i+2:]
+
+	switch key {
+	case "kind":
+		failErr.Kind = fail.Define(value)
+	case "code":
+		failErr.Code = parseValue(value)
+	case "ignorable":
+		failErr.Ignorable = value == "true"
+	case "severity":
+		failErr.Severity, _ = fail.ParseSeverity(value)
+	case "retryable":
+		failErr.Retryable = value == "true"
+	case "retry_after":
+		failErr.RetryAfter, _ = time.ParseDuration(value)
+	case "tags":
+		failErr.Tags = strings.Split(value, ", ")
+	case "params":
+		failErr.Params = fail.H{}
+		for _, pair := range strings.Split(value, ", ") {
+			if j := strings.Index(pair, "="); j >= 0 {
+				failErr.Params.Set(pair[:j], parseValue(pair[j+1:]))
+			}
+		}
+	case "labels":
+		failErr.Labels = map[string]string{}
+		for _, pair := range strings.Split(value, ", ") {
+			if j := strings.Index(pair, "="); j >= 0 {
+				failErr.Labels[pair[:j]] = pair[j+1:]
+			}
+		}
+	case "goroutine":
+		failErr.GoroutineID, _ = strconv.ParseInt(value, 10, 64)
+	case "created_at":
+		failErr.CreatedAt, _ = time.Parse(time.RFC3339Nano, value)
+	case "wrapped_at":
+		failErr.WrappedAt, _ = time.Parse(time.RFC3339Nano, value)
+	}
+}
+
+// parseValue returns the value as int if possible, or string otherwise
+func parseValue(s string) interface{} {
+	if n, err := strconv.Atoi(s); err == nil {
+		return n
+	}
+	return s
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/srvc/fail/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	f, err := os.Open("testdata/app.log")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	errs, err := Parse(f)
+	assert.NoError(t, err)
+	if !assert.Len(t, errs, 4) {
+		return
+	}
+
+	t.Run("json", func(t *testing.T) {
+		err := errs[0]
+		assert.Equal(t, &fail.DecodedError{Type: "*errors.errorString", Message: "not found"}, err.Err)
+		assert.Equal(t, "failed to find user: not found", err.Error())
+		assert.Equal(t, 404, err.Code)
+		assert.Equal(t, []string{"user"}, err.Tags)
+		assert.Equal(t, fail.H{"user_id": 1}, err.Params)
+		assert.Equal(t, "not_found", err.Kind.ID())
+		assert.Equal(t, time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), err.CreatedAt)
+		assert.Equal(t, int64(7), err.GoroutineID)
+		assert.Equal(t, fail.StackTrace{
+			{Func: "(*Repo).FindUser", File: "example.com/app/repo.go", Line: 12},
+			{Func: "ServeHTTP", File: "net/http/server.go", Line: 2220},
+			{Func: "main", File: "example.com/app/main.go", Line: 20},
+		}, err.StackTrace)
+	})
+
+	t.Run("fail", func(t *testing.T) {
+		err := errs[2]
+		assert.Equal(t, "failed to find user: not found", err.Error())
+		assert.Equal(t, 404, err.Code)
+		assert.Equal(t, []string{"user"}, err.Tags)
+		assert.Equal(t, fail.H{"user_id": 2}, err.Params)
+		assert.Equal(t, "not_found", err.Kind.ID())
+		assert.Equal(t, fail.SeverityWarning, err.Severity)
+		assert.Equal(t, map[string]string{"handler": "users"}, err.Labels)
+		assert.Equal(t, int64(18), err.GoroutineID)
+		assert.Equal(t, time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), err.CreatedAt)
+		assert.Equal(t, time.Date(2020, 1, 1, 0, 0, 1, 500000000, time.UTC), err.WrappedAt)
+		assert.Equal(t, fail.StackTrace{
+			{Func: "(*Repo).FindUser", File: "example.com/app/repo.go", Line: 13},
+			{Func: "ServeHTTP", File: "net/http/server.go", Line: 2220},
+			{Func: "main", File: "example.com/app/main.go", Line: 20},
+		}, err.StackTrace)
+	})
+
+	t.Run("pkg/errors", func(t *testing.T) {
+		err := errs[3]
+		assert.Equal(t, "failed to call f1: origin", err.Error())
+		assert.Equal(t, []string{"failed to call f1"}, err.Messages)
+		assert.Equal(t, fail.StackTrace{
+			{Func: "example.com/app.f1", File: "/home/user/app/main.go", Line: 8},
+			{Func: "main.main", File: "/home/user/app/main.go", Line: 20},
+		}, err.StackTrace)
+	})
+}
+
+func TestParse_Remote(t *testing.T) {
+	input := strings.Join([]string{
+		"failed to call users: not found",
+		"--- remote (users) ---",
+		"(*Repo).FindUser",
+		"\texample.com/users/repo.go:12",
+		"--- remote ---",
+		"(*Proxy).ServeHTTP",
+		"\texample.com/proxy/proxy.go:30",
+		"--- local ---",
+		"(*Client).GetUser",
+		"\texample.com/app/client.go:40",
+	}, "\n")
+
+	errs, err := Parse(strings.NewReader(input))
+	assert.NoError(t, err)
+	if assert.Len(t, errs, 1) {
+		assert.Equal(t, fail.StackTrace{
+			{Func: "(*Repo).FindUser", File: "example.com/users/repo.go", Line: 12, Remote: true, Origin: fail.Origin{Service: "users"}},
+			{Func: "(*Proxy).ServeHTTP", File: "example.com/proxy/proxy.go", Line: 30, Remote: true},
+			{Func: "(*Client).GetUser", File: "example.com/app/client.go", Line: 40},
+		}, errs[0].StackTrace)
+		assert.Empty(t, errs[0].Messages)
+	}
+}
+
+func TestParse_NestedParams(t *testing.T) {
+	input := strings.Join([]string{
+		"origin",
+		"    params: method=GET, user.id=1, user.name=foo",
+		"main",
+		"\texample.com/app/main.go:20",
+	}, "\n")
+
+	errs, err := Parse(strings.NewReader(input))
+	assert.NoError(t, err)
+	if assert.Len(t, errs, 1) {
+		assert.Equal(t, fail.H{"method": "GET", "user": fail.H{"id": 1, "name": "foo"}}, errs[0].Params)
+	}
+}
+
+func TestParse_Noise(t *testing.T) {
+	input := strings.Join([]string{
+		`{"level":"info","msg":"started"}`,
+		`{"broken`,
+		"just a message",
+		"",
+		"another message",
+		"    with an indented line",
+	}, "\n")
+
+	errs, err := Parse(strings.NewReader(input))
+	assert.NoError(t, err)
+	assert.Empty(t, errs)
+}
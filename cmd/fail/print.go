@@ -0,0 +1,184 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/srvc/fail/v4"
+)
+
+const (
+	colorReset = "\x1b[0m"
+	colorBold  = "\x1b[1m"
+	colorDim   = "\x1b[2m"
+	colorRed   = "\x1b[31m"
+	colorCyan  = "\x1b[36m"
+)
+
+// Printer pretty-prints errors
+type Printer struct {
+	Out   io.Writer
+	Color bool
+
+	// InAppPrefixes are prefixes of files or functions that belong to the application.
+	// Every frame belongs to the application if it's empty.
+	InAppPrefixes []string
+	// All disables collapsing frames that don't belong to the application
+	All bool
+
+	// Source loads source code shown around frames in the application if it's not nil
+	Source *fail.SourceLoader
+}
+
+// Group is errors that have the same fingerprint
+type Group struct {
+	Fingerprint string
+	Err         *fail.Error
+	Count       int
+}
+
+// GroupErrors groups the errors by fingerprints in order of first appearance.
+// The first error is kept as the representative of each group.
+func GroupErrors(errs []*fail.Error) []*Group {
+	var groups []*Group
+	index := map[string]*Group{}
+
+	for _, err := range errs {
+		fp := err.Fingerprint()
+		if g, ok := index[fp]; ok {
+			g.Count++
+			continue
+		}
+		g := &Group{Fingerprint: fp, Err: err, Count: 1}
+		index[fp] = g
+		groups = append(groups, g)
+	}
+
+	return groups
+}
+
+// Print prints the error
+func (p *Printer) Print(err *fail.Error) {
+	p.print(err, "")
+}
+
+// PrintGroup prints the representative error of the group with its count
+func (p *Printer) PrintGroup(g *Group) {
+	p.print(g.Err, fmt.Sprintf("%d× %s", g.Count, g.Fingerprint))
+}
+
+func (p *Printer) print(err *fail.Error, header string) {
+	if header != "" {
+		fmt.Fprintln(p.Out, p.paint(colorCyan, header))
+	}
+	fmt.Fprintln(p.Out, p.paint(colorBold+colorRed, err.Error()))
+
+	p.printMetadata(err)
+	p.printStackTrace(err.StackTrace)
+	fmt.Fprintln(p.Out)
+}
+
+func (p *Printer) printMetadata(err *fail.Error) {
+	var lines [][2]string
+	if err.Kind != nil {
+		lines = append(lines, [2]string{"kind", err.Kind.ID()})
+	}
+	if err.Code != nil {
+		lines = append(lines, [2]string{"code", fmt.Sprint(err.Code)})
+	}
+	if err.Ignorable {
+		lines = append(lines, [2]string{"ignorable", "true"})
+	}
+	if err.Severity != fail.SeverityUnspecified {
+		lines = append(lines, [2]string{"severity", err.Severity.String()})
+	}
+	if err.Retryable {
+		lines = append(lines, [2]string{"retryable", "true"})
+	}
+	if err.RetryAfter != 0 {
+		lines = append(lines, [2]string{"retry_after", err.RetryAfter.String()})
+	}
+	if len(err.Tags) > 0 {
+		lines = append(lines, [2]string{"tags", strings.Join(err.Tags, ", ")})
+	}
+	for _, param := range err.Params.Flatten() {
+		lines = append(lines, [2]string{"params." + param.Path, fmt.Sprint(param.Value)})
+	}
+	for _, k := range sortedLabelKeys(err.Labels) {
+		lines = append(lines, [2]string{"labels." + k, err.Labels[k]})
+	}
+	if err.GoroutineID != 0 {
+		lines = append(lines, [2]string{"goroutine", fmt.Sprint(err.GoroutineID)})
+	}
+	if !err.CreatedAt.IsZero() {
+		lines = append(lines, [2]string{"created_at", err.CreatedAt.Format(time.RFC3339Nano)})
+	}
+	if !err.WrappedAt.IsZero() {
+		lines = append(lines, [2]string{"wrapped_at", err.WrappedAt.Format(time.RFC3339Nano)})
+	}
+
+	for _, l := range lines {
+		fmt.Fprintf(p.Out, "    %s %s\n", p.paint(colorDim, l[0]+":"), l[1])
+	}
+}
+
+func (p *Printer) printStackTrace(st fail.StackTrace) {
+	collapsed := 0
+	flush := func() {
+		if collapsed > 0 {
+			fmt.Fprintln(p.Out, p.paint(colorDim, fmt.Sprintf("    ... %d framework frames", collapsed)))
+			collapsed = 0
+		}
+	}
+
+	for i, f := range st {
+		if sep := st.Separator(i); sep != "" {
+			flush()
+			fmt.Fprintf(p.Out, "  %s\n", p.paint(colorCyan, sep))
+		}
+
+		inApp := f.InApp(p.InAppPrefixes...)
+		if !inApp && !p.All {
+			collapsed++
+			continue
+		}
+		flush()
+
+		if inApp {
+			fmt.Fprintf(p.Out, "  %s\n", p.paint(colorBold, f.Func))
+		} else {
+			fmt.Fprintf(p.Out, "  %s\n", f.Func)
+		}
+		fmt.Fprintf(p.Out, "    %s\n", p.paint(colorDim, fmt.Sprintf("%s:%d", f.File, f.Line)))
+
+		if inApp && !f.Remote && p.Source != nil {
+			p.printSource(f)
+		}
+	}
+	flush()
+}
+
+// printSource prints lines around the line of the frame if the file is found
+func (p *Printer) printSource(f fail.Frame) {
+	src, ok := p.Source.Load(f)
+	if !ok {
+		return
+	}
+
+	for _, line := range src.Lines() {
+		color := colorDim
+		if line.Current {
+			color = colorRed
+		}
+		fmt.Fprintln(p.Out, p.paint(color, fmt.Sprintf("      %5d | %s", line.Number, line.Text)))
+	}
+}
+
+func (p *Printer) paint(color, s string) string {
+	if !p.Color {
+		return s
+	}
+	return color + s + colorReset
+}
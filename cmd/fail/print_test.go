@@ -0,0 +1,173 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/srvc/fail/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+var testError = &fail.Error{
+	Err:      errors.New("not found"),
+	Messages: []string{"failed to find user"},
+	Code:     404,
+	Tags:     []string{"user"},
+	Params:   fail.H{"user_id": 1},
+	Labels:   map[string]string{"handler": "users"},
+	StackTrace: fail.StackTrace{
+		{Func: "(*Repo).FindUser", File: "example.com/app/repo.go", Line: 10},
+		{Func: "ServeHTTP", File: "net/http/server.go", Line: 2220},
+		{Func: "(*conn).serve", File: "net/http/server.go", Line: 1830},
+		{Func: "main", File: "example.com/app/main.go", Line: 20},
+	},
+}
+
+func openTestdata(name string) (io.ReadCloser, error) {
+	return os.Open(filepath.Join("testdata", name))
+}
+
+func TestPrinter_Print(t *testing.T) {
+	cases := []struct {
+		test    string
+		printer Printer
+		want    string
+	}{
+		{
+			test:    "collapse",
+			printer: Printer{InAppPrefixes: []string{"example.com/app"}},
+			want: `failed to find user: not found
+    code: 404
+    tags: user
+    params.user_id: 1
+    labels.handler: users
+  (*Repo).FindUser
+    example.com/app/repo.go:10
+    ... 2 framework frames
+  main
+    example.com/app/main.go:20
+
+`,
+		},
+		{
+			test:    "all",
+			printer: Printer{InAppPrefixes: []string{"example.com/app"}, All: true},
+			want: `failed to find user: not found
+    code: 404
+    tags: user
+    params.user_id: 1
+    labels.handler: users
+  (*Repo).FindUser
+    example.com/app/repo.go:10
+  ServeHTTP
+    net/http/server.go:2220
+  (*conn).serve
+    net/http/server.go:1830
+  main
+    example.com/app/main.go:20
+
+`,
+		},
+		{
+			test:    "source",
+			printer: Printer{InAppPrefixes: []string{"example.com/app/repo.go"}, Source: fail.NewSourceLoader(1, openTestdata).WithModules("example.com/app")},
+			want: `failed to find user: not found
+    code: 404
+    tags: user
+    params.user_id: 1
+    labels.handler: users
+  (*Repo).FindUser
+    example.com/app/repo.go:10
+          9 | 	if err != nil {
+         10 | 		return nil, fail.Wrap(err, fail.WithMessage("failed to find user"))
+         11 | 	}
+    ... 3 framework frames
+
+`,
+		},
+		{
+			test:    "color",
+			printer: Printer{InAppPrefixes: []string{"example.com/app/repo.go"}, Color: true},
+			want: "\x1b[1m\x1b[31mfailed to find user: not found\x1b[0m\n" +
+				"    \x1b[2mcode:\x1b[0m 404\n" +
+				"    \x1b[2mtags:\x1b[0m user\n" +
+				"    \x1b[2mparams.user_id:\x1b[0m 1\n" +
+				"    \x1b[2mlabels.handler:\x1b[0m users\n" +
+				"  \x1b[1m(*Repo).FindUser\x1b[0m\n" +
+				"    \x1b[2mexample.com/app/repo.go:10\x1b[0m\n" +
+				"\x1b[2m    ... 3 framework frames\x1b[0m\n" +
+				"\n",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.test, func(t *testing.T) {
+			var buf bytes.Buffer
+			c.printer.Out = &buf
+			c.printer.Print(testError)
+			assert.Equal(t, c.want, buf.String())
+		})
+	}
+}
+
+func TestPrinter_Print_Remote(t *testing.T) {
+	err := &fail.Error{
+		Err: errors.New("not found"),
+		StackTrace: fail.StackTrace{
+			{Func: "(*Repo).FindUser", File: "example.com/app/repo.go", Line: 10, Remote: true, Origin: fail.Origin{Service: "users"}},
+			{Func: "ServeHTTP", File: "net/http/server.go", Line: 2220, Remote: true, Origin: fail.Origin{Service: "users"}},
+			{Func: "ServeHTTP", File: "net/http/server.go", Line: 2220},
+			{Func: "main", File: "example.com/app/main.go", Line: 20},
+		},
+	}
+
+	var buf bytes.Buffer
+	p := Printer{Out: &buf, InAppPrefixes: []string{"example.com/app"}, Source: fail.NewSourceLoader(1, openTestdata).WithModules("example.com/app")}
+	p.Print(err)
+	assert.Equal(t, `not found
+  --- remote (users) ---
+  (*Repo).FindUser
+    example.com/app/repo.go:10
+    ... 1 framework frames
+  --- local ---
+    ... 1 framework frames
+  main
+    example.com/app/main.go:20
+
+`, buf.String())
+}
+
+func TestGroupErrors(t *testing.T) {
+	other := testError.Copy()
+	other.Params = fail.H{"user_id": 2}
+	other.StackTrace = append(fail.StackTrace{}, testError.StackTrace...)
+	other.StackTrace[0].Line = 11
+
+	another := &fail.Error{Err: errors.New("timeout")}
+
+	groups := GroupErrors([]*fail.Error{testError, another, other})
+	if assert.Len(t, groups, 2) {
+		assert.Equal(t, testError, groups[0].Err)
+		assert.Equal(t, 2, groups[0].Count)
+		assert.Equal(t, testError.Fingerprint(), groups[0].Fingerprint)
+		assert.Equal(t, another, groups[1].Err)
+		assert.Equal(t, 1, groups[1].Count)
+	}
+}
+
+func TestRun(t *testing.T) {
+	var out bytes.Buffer
+	err := run([]string{"-group", "-color", "never", "-app", "example.com/app", "testdata/app.log"}, nil, &out)
+	assert.NoError(t, err)
+	assert.Contains(t, out.String(), "2× ")
+	assert.Contains(t, out.String(), "1× ")
+
+	t.Run("invalid color", func(t *testing.T) {
+		err := run([]string{"-color", "sometimes"}, nil, &out)
+		assert.Error(t, err)
+	})
+}
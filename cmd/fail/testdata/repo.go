@@ -0,0 +1,14 @@
+package app
+
+type Repo struct{}
+
+type User struct{}
+
+func (r *Repo) FindUser(id int) (*User, error) {
+	user, err := r.find(id)
+	if err != nil {
+		return nil, fail.Wrap(err, fail.WithMessage("failed to find user"))
+	}
+	return user, nil
+	return nil, nil
+}
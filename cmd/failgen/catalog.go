@@ -0,0 +1,178 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Catalog is a definition of errors.
+// It is decoded from YAML, or JSON as a subset of YAML.
+type Catalog struct {
+	// Package is the package name of the generated Go code
+	Package string `yaml:"package"`
+	// DefaultLocale is the locale of messages used by generated constructors
+	DefaultLocale string `yaml:"default_locale"`
+	// Errors are definitions of errors
+	Errors []*Definition `yaml:"errors"`
+}
+
+// Definition is a definition of an error
+type Definition struct {
+	// ID is a stable machine-readable ID of the error
+	ID string `yaml:"id"`
+	// Name is the identifier used in the generated Go code.
+	// It's derived from ID if empty.
+	Name string `yaml:"name"`
+	// Description is a human-readable description of the error
+	Description string `yaml:"description"`
+	// Code is a value annotated with fail.WithCode
+	Code interface{} `yaml:"code"`
+	// HTTP is an HTTP status code mapped to the error
+	HTTP int `yaml:"http"`
+	// GRPC is a name of a gRPC status code mapped to the error, such as "NotFound"
+	GRPC string `yaml:"grpc"`
+	// Tags are annotated with fail.WithTags
+	Tags []string `yaml:"tags"`
+	// Ignorable is annotated with fail.WithIgnorable
+	Ignorable bool `yaml:"ignorable"`
+	// Messages are message templates for each locale
+	Messages map[string]string `yaml:"messages"`
+}
+
+var (
+	identRegexp       = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	idSeparatorRegexp = regexp.MustCompile(`[^A-Za-z0-9]+`)
+)
+
+// grpcCodes is a list of names of google.golang.org/grpc/codes
+var grpcCodes = map[string]bool{
+	"OK": true, "Canceled": true, "Unknown": true, "InvalidArgument": true,
+	"DeadlineExceeded": true, "NotFound": true, "AlreadyExists": true, "PermissionDenied": true,
+	"ResourceExhausted": true, "FailedPrecondition": true, "Aborted": true, "OutOfRange": true,
+	"Unimplemented": true, "Internal": true, "Unavailable": true, "DataLoss": true,
+	"Unauthenticated": true,
+}
+
+// LoadCatalog reads a catalog from the file
+func LoadCatalog(path string) (*Catalog, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseCatalog(data)
+}
+
+// ParseCatalog decodes and validates a catalog
+func ParseCatalog(data []byte) (*Catalog, error) {
+	var c Catalog
+	if err := yaml.UnmarshalStrict(data, &c); err != nil {
+		return nil, err
+	}
+	if err := c.normalize(); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// normalize fills default values and validates the catalog
+func (c *Catalog) normalize() error {
+	if c.Package == "" {
+		return fmt.Errorf("package is required")
+	}
+	if !identRegexp.MatchString(c.Package) {
+		return fmt.Errorf("package %q is not a valid identifier", c.Package)
+	}
+	if c.DefaultLocale == "" {
+		c.DefaultLocale = "en"
+	}
+
+	ids := map[string]bool{}
+	names := map[string]bool{}
+	for i, d := range c.Errors {
+		if d.ID == "" {
+			return fmt.Errorf("errors[%d]: id is required", i)
+		}
+		if ids[d.ID] {
+			return fmt.Errorf("errors[%d]: duplicated id %q", i, d.ID)
+		}
+		ids[d.ID] = true
+
+		if d.Name == "" {
+			d.Name = camelize(d.ID)
+		}
+		if !identRegexp.MatchString(d.Name) {
+			return fmt.Errorf("errors[%d]: name %q is not a valid identifier", i, d.Name)
+		}
+		if names[d.Name] {
+			return fmt.Errorf("errors[%d]: duplicated name %q", i, d.Name)
+		}
+		names[d.Name] = true
+
+		if d.GRPC != "" && !grpcCodes[d.GRPC] {
+			return fmt.Errorf("errors[%d]: unknown gRPC code %q", i, d.GRPC)
+		}
+		if _, err := d.CodeLiteral(); err != nil {
+			return fmt.Errorf("errors[%d]: %v", i, err)
+		}
+	}
+
+	return nil
+}
+
+// UsesGRPC reports whether any error is mapped to a gRPC status code
+func (c *Catalog) UsesGRPC() bool {
+	for _, d := range c.Errors {
+		if d.GRPC != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// Locales returns the sorted locales of the definition
+func (d *Definition) Locales() []string {
+	locales := make([]string, 0, len(d.Messages))
+	for l := range d.Messages {
+		locales = append(locales, l)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// CodeLiteral returns the code as a Go literal.
+// It returns an empty string if the code is not set.
+func (d *Definition) CodeLiteral() (string, error) {
+	switch v := d.Code.(type) {
+	case nil:
+		return "", nil
+	case int:
+		return strconv.Itoa(v), nil
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case string:
+		return strconv.Quote(v), nil
+	default:
+		return "", fmt.Errorf("unsupported code %v", v)
+	}
+}
+
+// camelize converts an ID like "user_not_found" into "UserNotFound"
+func camelize(id string) string {
+	var b strings.Builder
+	for _, w := range idSeparatorRegexp.Split(id, -1) {
+		if w == "" {
+			continue
+		}
+		b.WriteString(strings.ToUpper(w[:1]))
+		b.WriteString(w[1:])
+	}
+	return b.String()
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCatalog(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		c, err := ParseCatalog([]byte(`
+package: apperrors
+errors:
+  - id: user-not_found
+`))
+		assert.NoError(t, err)
+		assert.Equal(t, "en", c.DefaultLocale)
+		assert.Equal(t, "UserNotFound", c.Errors[0].Name)
+		assert.False(t, c.UsesGRPC())
+	})
+
+	invalids := map[string]string{
+		"no package":       "errors: []",
+		"invalid package":  "package: app-errors",
+		"unknown field":    "package: apperrors\nfoo: bar",
+		"no id":            "package: apperrors\nerrors: [{code: 404}]",
+		"duplicated id":    "package: apperrors\nerrors: [{id: foo}, {id: foo}]",
+		"duplicated name":  "package: apperrors\nerrors: [{id: foo}, {id: bar, name: Foo}]",
+		"invalid name":     "package: apperrors\nerrors: [{id: 404}]",
+		"unknown gRPC":     "package: apperrors\nerrors: [{id: foo, grpc: Missing}]",
+		"unsupported code": "package: apperrors\nerrors: [{id: foo, code: [1, 2]}]",
+	}
+
+	for name, data := range invalids {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParseCatalog([]byte(data))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestCamelize(t *testing.T) {
+	tests := map[string]string{
+		"user_not_found": "UserNotFound",
+		"user-not-found": "UserNotFound",
+		"user.notFound":  "UserNotFound",
+		"__internal__":   "Internal",
+	}
+
+	for input, expect := range tests {
+		assert.Equal(t, expect, camelize(input))
+	}
+}
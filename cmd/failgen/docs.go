@@ -0,0 +1,142 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v2"
+)
+
+var markdownTemplate = template.Must(template.New("markdown").Funcs(template.FuncMap{
+	"cell": markdownCell,
+	"join": markdownTags,
+	"trim": strings.TrimSpace,
+	"code": func(d *Definition) string {
+		code, _ := d.CodeLiteral()
+		return code
+	},
+}).Parse(`<!-- Code generated by failgen. DO NOT EDIT. -->
+
+# Errors
+
+| ID | Code | HTTP | gRPC | Tags | Ignorable | Description |
+|----|------|------|------|------|-----------|-------------|
+{{- range .Errors}}
+| [` + "`{{.ID}}`" + `](#{{.ID}}) | {{cell (code .)}} | {{if .HTTP}}{{.HTTP}}{{end}} | {{.GRPC}} | {{cell (join .Tags)}} | {{if .Ignorable}}yes{{end}} | {{cell .Description}} |
+{{- end}}
+{{range .Errors}}
+## {{.ID}}
+{{- with trim .Description}}
+
+{{.}}
+{{- end}}
+{{- if .Messages}}
+
+| Locale | Message |
+|--------|---------|
+{{- $d := .}}
+{{- range .Locales}}
+| {{.}} | {{index $d.Messages . | cell}} |
+{{- end}}
+{{- end}}
+{{end}}`))
+
+// GenerateMarkdown generates Markdown documentation of the catalog
+func GenerateMarkdown(c *Catalog) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := markdownTemplate.Execute(&buf, c); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// markdownCell escapes a string to be placed in a table cell
+func markdownCell(s string) string {
+	s = strings.TrimSpace(s)
+	s = strings.Replace(s, "|", `\|`, -1)
+	return strings.Replace(s, "\n", " ", -1)
+}
+
+// markdownTags formats tags as inline code
+func markdownTags(tags []string) string {
+	quoted := make([]string, len(tags))
+	for i, t := range tags {
+		quoted[i] = "`" + t + "`"
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// GenerateOpenAPI generates an OpenAPI 3 document of the catalog.
+// Each error is described as a reusable response in components,
+// and all IDs are enumerated in the ErrorID schema.
+func GenerateOpenAPI(c *Catalog) ([]byte, error) {
+	ids := make([]string, len(c.Errors))
+	responses := yaml.MapSlice{}
+	for i, d := range c.Errors {
+		ids[i] = d.ID
+
+		description := d.Description
+		if description == "" {
+			description = d.ID
+		}
+		example := yaml.MapSlice{{Key: "id", Value: d.ID}}
+		if msg, ok := d.Messages[c.DefaultLocale]; ok {
+			example = append(example, yaml.MapItem{Key: "message", Value: msg})
+		}
+
+		extensions := yaml.MapSlice{}
+		if d.HTTP != 0 {
+			extensions = append(extensions, yaml.MapItem{Key: "x-http-status", Value: d.HTTP})
+		}
+		if d.GRPC != "" {
+			extensions = append(extensions, yaml.MapItem{Key: "x-grpc-code", Value: d.GRPC})
+		}
+
+		response := yaml.MapSlice{
+			{Key: "description", Value: description},
+			{Key: "content", Value: yaml.MapSlice{
+				{Key: "application/json", Value: yaml.MapSlice{
+					{Key: "schema", Value: yaml.MapSlice{
+						{Key: "$ref", Value: "#/components/schemas/Error"},
+					}},
+					{Key: "example", Value: example},
+				}},
+			}},
+		}
+		responses = append(responses, yaml.MapItem{Key: d.Name, Value: append(response, extensions...)})
+	}
+
+	doc := yaml.MapSlice{
+		{Key: "openapi", Value: "3.0.3"},
+		{Key: "info", Value: yaml.MapSlice{
+			{Key: "title", Value: fmt.Sprintf("%s errors", c.Package)},
+			{Key: "version", Value: "1.0.0"},
+		}},
+		{Key: "paths", Value: yaml.MapSlice{}},
+		{Key: "components", Value: yaml.MapSlice{
+			{Key: "schemas", Value: yaml.MapSlice{
+				{Key: "ErrorID", Value: yaml.MapSlice{
+					{Key: "type", Value: "string"},
+					{Key: "enum", Value: ids},
+				}},
+				{Key: "Error", Value: yaml.MapSlice{
+					{Key: "type", Value: "object"},
+					{Key: "required", Value: []string{"id"}},
+					{Key: "properties", Value: yaml.MapSlice{
+						{Key: "id", Value: yaml.MapSlice{{Key: "$ref", Value: "#/components/schemas/ErrorID"}}},
+						{Key: "message", Value: yaml.MapSlice{{Key: "type", Value: "string"}}},
+					}},
+				}},
+			}},
+			{Key: "responses", Value: responses},
+		}},
+	}
+
+	out, err := yaml.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte("# Code generated by failgen. DO NOT EDIT.\n"), out...), nil
+}
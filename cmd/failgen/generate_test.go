@@ -0,0 +1,69 @@
+package main
+
+import (
+	"flag"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+func TestGenerate(t *testing.T) {
+	c, err := LoadCatalog(filepath.Join("testdata", "catalog.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		test     string
+		golden   string
+		generate func(*Catalog) ([]byte, error)
+	}{
+		{test: "go", golden: "apperrors.go.golden", generate: GenerateGo},
+		{test: "markdown", golden: "ERRORS.md.golden", generate: GenerateMarkdown},
+		{test: "openapi", golden: "openapi.yaml.golden", generate: GenerateOpenAPI},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.test, func(t *testing.T) {
+			got, err := tc.generate(c)
+			assert.NoError(t, err)
+
+			path := filepath.Join("testdata", tc.golden)
+			if *update {
+				if err := ioutil.WriteFile(path, got, 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			want, err := ioutil.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, string(want), string(got))
+		})
+	}
+}
+
+func TestRun(t *testing.T) {
+	dir, err := ioutil.TempDir("", "failgen")
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := filepath.Join(dir, "errors_gen.go")
+
+	err = run([]string{"-in", filepath.Join("testdata", "catalog.json"), "-out", out, "-pkg", "myerrors"})
+	assert.NoError(t, err)
+
+	data, err := ioutil.ReadFile(out)
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), "package myerrors\n")
+	assert.Contains(t, string(data), `ErrUserNotFound = fail.Define("user_not_found", fail.WithCode(404), fail.WithTags("user"))`)
+	assert.NotContains(t, string(data), "grpc")
+
+	err = run([]string{"-out", out})
+	assert.Error(t, err)
+}
@@ -0,0 +1,10 @@
+module github.com/srvc/fail/v4/cmd/failgen
+
+go 1.23.0
+
+require (
+	github.com/stretchr/testify v1.12.1
+	gopkg.in/yaml.v2 v2.4.0
+)
+
+require go.yaml.in/yaml/v3 v3.0.5 // indirect
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"bytes"
+	"go/format"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+var goTemplate = template.Must(template.New("go").Funcs(template.FuncMap{
+	"quote":      strconv.Quote,
+	"annotators": annotators,
+	"comment":    comment,
+}).Parse(`// Code generated by failgen. DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+	"github.com/srvc/fail/v4"
+{{- if .UsesGRPC}}
+	"google.golang.org/grpc/codes"
+{{- end}}
+)
+
+var (
+{{- range .Errors}}
+	{{comment (printf "Err%s is an error of %q." .Name .ID) .Description}}
+	Err{{.Name}} = fail.Define({{quote .ID}}{{annotators .}})
+{{- end}}
+)
+{{range .Errors}}
+// New{{.Name}} returns a new error of Err{{.Name}} with the stack trace from the caller.
+func New{{.Name}}(args ...interface{}) error {
+	return Err{{.Name}}.ErrorfSkip(1, messageTemplates[{{quote .ID}}][{{quote $.DefaultLocale}}], args...)
+}
+{{end}}
+var messageTemplates = map[string]map[string]string{
+{{- range .Errors}}
+	{{quote .ID}}: {
+		{{- $d := .}}
+		{{- range .Locales}}
+		{{quote .}}: {{index $d.Messages . | quote}},
+		{{- end}}
+		{{- if not (index .Messages $.DefaultLocale)}}
+		{{quote $.DefaultLocale}}: {{quote .ID}},
+		{{- end}}
+	},
+{{- end}}
+}
+
+var httpStatuses = map[string]int{
+{{- range .Errors}}{{if .HTTP}}
+	{{quote .ID}}: {{.HTTP}},
+{{- end}}{{end}}
+}
+{{- if .UsesGRPC}}
+
+var grpcCodes = map[string]codes.Code{
+{{- range .Errors}}{{if .GRPC}}
+	{{quote .ID}}: codes.{{.GRPC}},
+{{- end}}{{end}}
+}
+{{- end}}
+
+// MessageTemplate returns the message template of the error for the locale.
+func MessageTemplate(err error, locale string) (string, bool) {
+	msg, ok := messageTemplates[kindID(err)][locale]
+	return msg, ok
+}
+
+// HTTPStatus returns the HTTP status code mapped to the error.
+func HTTPStatus(err error) (int, bool) {
+	status, ok := httpStatuses[kindID(err)]
+	return status, ok
+}
+{{- if .UsesGRPC}}
+
+// GRPCCode returns the gRPC status code mapped to the error.
+func GRPCCode(err error) (codes.Code, bool) {
+	code, ok := grpcCodes[kindID(err)]
+	return code, ok
+}
+{{- end}}
+
+func kindID(err error) string {
+	if failErr := fail.Unwrap(err); failErr != nil && failErr.Kind != nil {
+		return failErr.Kind.ID()
+	}
+	return ""
+}
+`))
+
+// GenerateGo generates Go code of the catalog
+func GenerateGo(c *Catalog) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := goTemplate.Execute(&buf, c); err != nil {
+		return nil, err
+	}
+	return format.Source(buf.Bytes())
+}
+
+// annotators returns the arguments of fail.Define for the definition
+func annotators(d *Definition) string {
+	var args []string
+
+	if code, _ := d.CodeLiteral(); code != "" {
+		args = append(args, "fail.WithCode("+code+")")
+	}
+	if len(d.Tags) > 0 {
+		tags := make([]string, len(d.Tags))
+		for i, t := range d.Tags {
+			tags[i] = strconv.Quote(t)
+		}
+		args = append(args, "fail.WithTags("+strings.Join(tags, ", ")+")")
+	}
+	if d.Ignorable {
+		args = append(args, "fail.WithIgnorable()")
+	}
+
+	if len(args) == 0 {
+		return ""
+	}
+	return ", " + strings.Join(args, ", ")
+}
+
+// comment formats lines as a Go comment
+func comment(lines ...string) string {
+	var out []string
+	for _, l := range lines {
+		for _, s := range strings.Split(strings.TrimSpace(l), "\n") {
+			if s != "" {
+				out = append(out, "// "+s)
+			}
+		}
+	}
+	return strings.Join(out, "\n\t")
+}
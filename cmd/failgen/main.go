@@ -0,0 +1,85 @@
+// Command failgen generates Go code and documentation of errors from a catalog file.
+//
+// A catalog is written in YAML or JSON:
+//
+//	package: apperrors
+//	default_locale: en
+//	errors:
+//	  - id: user_not_found
+//	    description: The user does not exist.
+//	    code: 404
+//	    http: 404
+//	    grpc: NotFound
+//	    tags: [user]
+//	    ignorable: true
+//	    messages:
+//	      en: user %d not found
+//	      ja: ユーザー %d が見つかりません
+//
+// Usage:
+//
+//	//go:generate go run github.com/srvc/fail/v4/cmd/failgen -in errors.yaml -out errors_gen.go -markdown ERRORS.md
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+)
+
+func main() {
+	if err := run(os.Args[1:]); err != nil {
+		fmt.Fprintf(os.Stderr, "failgen: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string) error {
+	fs := flag.NewFlagSet("failgen", flag.ContinueOnError)
+	in := fs.String("in", "", "path to the catalog file (required)")
+	out := fs.String("out", "", "path to the generated Go file")
+	pkg := fs.String("pkg", "", "package name of the generated Go file (overrides the catalog)")
+	markdown := fs.String("markdown", "", "path to the generated Markdown documentation")
+	openapi := fs.String("openapi", "", "path to the generated OpenAPI document")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *in == "" {
+		fs.Usage()
+		return fmt.Errorf("-in is required")
+	}
+
+	c, err := LoadCatalog(*in)
+	if err != nil {
+		return fmt.Errorf("failed to load %s: %v", *in, err)
+	}
+	if *pkg != "" {
+		c.Package = *pkg
+	}
+
+	outputs := []struct {
+		path     string
+		generate func(*Catalog) ([]byte, error)
+	}{
+		{*out, GenerateGo},
+		{*markdown, GenerateMarkdown},
+		{*openapi, GenerateOpenAPI},
+	}
+
+	for _, o := range outputs {
+		if o.path == "" {
+			continue
+		}
+		data, err := o.generate(c)
+		if err != nil {
+			return fmt.Errorf("failed to generate %s: %v", o.path, err)
+		}
+		if err := ioutil.WriteFile(o.path, data, 0644); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
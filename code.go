@@ -0,0 +1,86 @@
+package fail
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// CanonicalCode is a code of errors independent of transports.
+// The values are the same as the status codes of gRPC, so it's annotated with WithCode
+// and converted into gRPC and HTTP status codes at the edge of the application.
+type CanonicalCode int
+
+// Canonical codes
+const (
+	CodeCanceled CanonicalCode = iota + 1
+	CodeUnknown
+	CodeInvalidArgument
+	CodeDeadlineExceeded
+	CodeNotFound
+	CodeAlreadyExists
+	CodePermissionDenied
+	CodeResourceExhausted
+	CodeFailedPrecondition
+	CodeAborted
+	CodeOutOfRange
+	CodeUnimplemented
+	CodeInternal
+	CodeUnavailable
+	CodeDataLoss
+	CodeUnauthenticated
+)
+
+var canonicalCodeNames = map[CanonicalCode]string{
+	CodeCanceled:           "Canceled",
+	CodeUnknown:            "Unknown",
+	CodeInvalidArgument:    "InvalidArgument",
+	CodeDeadlineExceeded:   "DeadlineExceeded",
+	CodeNotFound:           "NotFound",
+	CodeAlreadyExists:      "AlreadyExists",
+	CodePermissionDenied:   "PermissionDenied",
+	CodeResourceExhausted:  "ResourceExhausted",
+	CodeFailedPrecondition: "FailedPrecondition",
+	CodeAborted:            "Aborted",
+	CodeOutOfRange:         "OutOfRange",
+	CodeUnimplemented:      "Unimplemented",
+	CodeInternal:           "Internal",
+	CodeUnavailable:        "Unavailable",
+	CodeDataLoss:           "DataLoss",
+	CodeUnauthenticated:    "Unauthenticated",
+}
+
+// String returns the name of the code
+func (c CanonicalCode) String() string {
+	if name, ok := canonicalCodeNames[c]; ok {
+		return name
+	}
+	return fmt.Sprintf("CanonicalCode(%d)", int(c))
+}
+
+// MarshalText implements encoding.TextMarshaler.
+// It returns the name of the code, or the number for codes without names.
+func (c CanonicalCode) MarshalText() ([]byte, error) {
+	if name, ok := canonicalCodeNames[c]; ok {
+		return []byte(name), nil
+	}
+	return []byte(strconv.Itoa(int(c))), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+// It accepts the name of the code, case-insensitively, or the number.
+func (c *CanonicalCode) UnmarshalText(text []byte) error {
+	s := string(text)
+	for code, name := range canonicalCodeNames {
+		if strings.EqualFold(name, s) {
+			*c = code
+			return nil
+		}
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return fmt.Errorf("unknown canonical code %q", s)
+	}
+	*c = CanonicalCode(n)
+	return nil
+}
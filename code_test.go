@@ -0,0 +1,32 @@
+package fail
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCanonicalCode_String(t *testing.T) {
+	assert.Equal(t, "NotFound", CodeNotFound.String())
+	assert.Equal(t, "Unauthenticated", CodeUnauthenticated.String())
+	assert.Equal(t, "CanonicalCode(0)", CanonicalCode(0).String())
+}
+
+func TestCanonicalCode_Text(t *testing.T) {
+	text, err := CodeUnavailable.MarshalText()
+	assert.NoError(t, err)
+	assert.Equal(t, "Unavailable", string(text))
+
+	text, err = CanonicalCode(99).MarshalText()
+	assert.NoError(t, err)
+	assert.Equal(t, "99", string(text))
+
+	for _, s := range []string{"Unavailable", "unavailable", "14"} {
+		var code CanonicalCode
+		assert.NoError(t, code.UnmarshalText([]byte(s)), s)
+		assert.Equal(t, CodeUnavailable, code, s)
+	}
+
+	var code CanonicalCode
+	assert.EqualError(t, code.UnmarshalText([]byte("Unavailabel")), `unknown canonical code "Unavailabel"`)
+}
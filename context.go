@@ -0,0 +1,72 @@
+package fail
+
+import (
+	"context"
+	"sync"
+)
+
+// ContextExtractor is a function that extracts params from a context
+type ContextExtractor func(ctx context.Context) H
+
+var (
+	contextExtractorsMu sync.RWMutex
+	contextExtractors   []ContextExtractor
+)
+
+// RegisterContextExtractor registers an extractor that is applied to every error
+// annotated with WithContext or wrapped with WrapContext.
+// Extractors are applied in the order they were registered.
+func RegisterContextExtractor(extractor ContextExtractor) {
+	contextExtractorsMu.Lock()
+	defer contextExtractorsMu.Unlock()
+	contextExtractors = append(contextExtractors, extractor)
+}
+
+type contextTagsKey struct{}
+
+// ContextWithTags returns a copy of ctx that carries the tags.
+// They are annotated to errors by WithContext in addition to the tags already in ctx.
+func ContextWithTags(ctx context.Context, tags ...string) context.Context {
+	current := tagsFromContext(ctx)
+	merged := make([]string, 0, len(current)+len(tags))
+	merged = append(merged, current...)
+	merged = append(merged, tags...)
+	return context.WithValue(ctx, contextTagsKey{}, merged)
+}
+
+// tagsFromContext returns tags attached by ContextWithTags
+func tagsFromContext(ctx context.Context) []string {
+	tags, _ := ctx.Value(contextTagsKey{}).([]string)
+	return tags
+}
+
+// WithContext annotates an error with tags attached to the context
+// and with params retrieved by the registered context extractors
+func WithContext(ctx context.Context) Annotator {
+	return func(err *Error) {
+		if ctx == nil {
+			return
+		}
+
+		if tags := tagsFromContext(ctx); len(tags) > 0 {
+			WithTags(tags...)(err)
+		}
+
+		contextExtractorsMu.RLock()
+		extractors := contextExtractors
+		contextExtractorsMu.RUnlock()
+
+		for _, extract := range extractors {
+			if h := extract(ctx); len(h) > 0 {
+				WithParams(h)(err)
+			}
+		}
+	}
+}
+
+// WrapContext is like Wrap, but it also annotates the error with WithContext.
+// The context annotations are applied before the specified annotators.
+// It returns nil if err is nil.
+func WrapContext(ctx context.Context, err error, annotators ...Annotator) error {
+	return wrap(err, 0, append([]Annotator{WithContext(ctx)}, annotators...))
+}
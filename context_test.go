@@ -0,0 +1,87 @@
+package fail
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testRequestIDKey struct{}
+
+func init() {
+	RegisterContextExtractor(func(ctx context.Context) H {
+		if id, ok := ctx.Value(testRequestIDKey{}).(string); ok {
+			return H{"request_id": id}
+		}
+		return nil
+	})
+}
+
+func TestWithContext(t *testing.T) {
+	t.Run("nil", func(t *testing.T) {
+		err := Wrap(nil, WithContext(context.Background()))
+		assert.Equal(t, nil, err)
+	})
+
+	t.Run("empty context", func(t *testing.T) {
+		err0 := errors.New("origin")
+
+		err1 := Wrap(err0, WithContext(context.Background()))
+
+		failErr := Unwrap(err1)
+		assert.Equal(t, err0, failErr.Err)
+		assert.Empty(t, failErr.Tags)
+		assert.Empty(t, failErr.Params)
+	})
+
+	t.Run("with extractors and tags", func(t *testing.T) {
+		ctx := context.WithValue(context.Background(), testRequestIDKey{}, "req-1")
+		ctx = ContextWithTags(ctx, "http")
+		ctx = ContextWithTags(ctx, "user")
+
+		err0 := errors.New("origin")
+
+		err1 := Wrap(err0, WithContext(ctx), WithParam("foo", 1))
+
+		failErr := Unwrap(err1)
+		assert.Equal(t, err0, failErr.Err)
+		assert.Equal(t, []string{"http", "user"}, failErr.Tags)
+		assert.Equal(t, H{"request_id": "req-1", "foo": 1}, failErr.Params)
+	})
+}
+
+func TestWrapContext(t *testing.T) {
+	t.Run("nil", func(t *testing.T) {
+		err := WrapContext(context.Background(), nil)
+		assert.Nil(t, err)
+	})
+
+	t.Run("bare", func(t *testing.T) {
+		ctx := context.WithValue(context.Background(), testRequestIDKey{}, "req-1")
+		err0 := errors.New("origin")
+
+		err1 := wrapContextOrigin(ctx, err0)
+		assert.Equal(t, "message: origin", err1.Error())
+
+		failErr := Unwrap(err1)
+		assert.Equal(t, err0, failErr.Err)
+		assert.Equal(t, H{"request_id": "req-1"}, failErr.Params)
+		assert.NotEmpty(t, failErr.StackTrace)
+		assert.Equal(t, "wrapContextOrigin", failErr.StackTrace[0].Func)
+	})
+
+	t.Run("annotators override context", func(t *testing.T) {
+		ctx := context.WithValue(context.Background(), testRequestIDKey{}, "req-1")
+
+		err := WrapContext(ctx, errors.New("origin"), WithParam("request_id", "req-2"))
+
+		failErr := Unwrap(err)
+		assert.Equal(t, H{"request_id": "req-2"}, failErr.Params)
+	})
+}
+
+func wrapContextOrigin(ctx context.Context, err error) error {
+	return WrapContext(ctx, err, WithMessage("message"))
+}
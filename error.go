@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 )
 
 const (
@@ -20,6 +21,12 @@ type Error struct {
 	Code interface{}
 	// Ignorable represents whether the error should be reported to administrators
 	Ignorable bool
+	// Severity is a level of the error annotated by WithSeverity
+	Severity Severity
+	// Retryable represents whether the operation that returned the error can be retried
+	Retryable bool
+	// RetryAfter is the duration to wait before retrying
+	RetryAfter time.Duration
 	// Tags represents tags of the error which is classified errors.
 	Tags []string
 	// Params is an annotated parameters of the error.
@@ -27,13 +34,24 @@ type Error struct {
 	// StackTrace is a stack trace of the original error
 	// from the point where it was created
 	StackTrace StackTrace
+	// Kind is a class of the error defined by Define
+	Kind *Kind
+	// CreatedAt is the time when the original error was created, or first wrapped if it's not derived from fail
+	CreatedAt time.Time
+	// WrappedAt is the time when the error was last wrapped
+	WrappedAt time.Time
+	// GoroutineID is the ID of the goroutine annotated by WithGoroutineID
+	GoroutineID int64
+	// Labels are the profiler labels annotated by WithLabels
+	Labels map[string]string
 }
 
 // New returns an error that formats as the given text.
 // It also records the stack trace at the point it was called.
 func New(text string) error {
-	err := &Error{Err: errors.New(text)}
+	err := &Error{Err: errors.New(text), CreatedAt: now()}
 	withStackTrace(0)(err)
+	runHooks(&createHooks, err)
 	return err
 }
 
@@ -41,8 +59,9 @@ func New(text string) error {
 // as a value that satisfies error.
 // It also records the stack trace at the point it was called.
 func Errorf(format string, args ...interface{}) error {
-	err := &Error{Err: fmt.Errorf(format, args...)}
+	err := &Error{Err: fmt.Errorf(format, args...), CreatedAt: now()}
 	withStackTrace(0)(err)
+	runHooks(&createHooks, err)
 	return err
 }
 
@@ -56,13 +75,21 @@ func (e *Error) Error() string {
 // Copy creates a copy of the current object
 func (e *Error) Copy() *Error {
 	return &Error{
-		Err:        e.Err,
-		Messages:   e.Messages,
-		Code:       e.Code,
-		Ignorable:  e.Ignorable,
-		Tags:       e.Tags,
-		Params:     e.Params,
-		StackTrace: e.StackTrace,
+		Err:         e.Err,
+		Messages:    e.Messages,
+		Code:        e.Code,
+		Ignorable:   e.Ignorable,
+		Severity:    e.Severity,
+		Retryable:   e.Retryable,
+		RetryAfter:  e.RetryAfter,
+		Tags:        e.Tags,
+		Params:      e.Params,
+		StackTrace:  e.StackTrace,
+		Kind:        e.Kind,
+		CreatedAt:   e.CreatedAt,
+		WrappedAt:   e.WrappedAt,
+		GoroutineID: e.GoroutineID,
+		Labels:      e.Labels,
 	}
 }
 
@@ -77,14 +104,36 @@ func (e *Error) LastMessage() string {
 // Unwrap provides compatibility for Go 1.13 error chains.
 func (e *Error) Unwrap() error { return e.Err }
 
+// Is reports whether the error is of the target kind.
+// It provides compatibility for Go 1.13 errors.Is.
+func (e *Error) Is(target error) bool {
+	k, ok := target.(*Kind)
+	return ok && e.Kind != nil && e.Kind.id == k.id
+}
+
 // Wrap returns an error annotated with a stack trace from the point it was called,
 // and with the specified annotators.
 // It returns nil if err is nil.
 func Wrap(err error, annotators ...Annotator) error {
+	return wrap(err, 0, annotators)
+}
+
+// WrapSkip is Wrap for helpers that wrap errors on behalf of their callers.
+// The skip is the number of frames to skip above the caller of WrapSkip,
+// so that the stack trace starts from the caller of the helper with 1.
+func WrapSkip(err error, skip int, annotators ...Annotator) error {
+	return wrap(err, skip, annotators)
+}
+
+// wrap is the implementation of Wrap.
+// The offset is the number of extra frames between the caller and wrap itself.
+func wrap(err error, offset int, annotators []Annotator) error {
 	if err == nil {
 		return nil
 	}
 
+	created := !isFailError(err)
+
 	failErr := Unwrap(err)
 	if failErr == nil {
 		failErr = &Error{
@@ -92,12 +141,20 @@ func Wrap(err error, annotators ...Annotator) error {
 		}
 	}
 
-	withStackTrace(0)(failErr)
+	withStackTrace(offset + 1)(failErr)
+	failErr.WrappedAt = now()
+
+	if created {
+		failErr.CreatedAt = failErr.WrappedAt
+		runHooks(&createHooks, failErr)
+	}
 
 	for _, f := range annotators {
 		f(failErr)
 	}
 
+	runHooks(&wrapHooks, failErr)
+
 	return failErr
 }
 
@@ -200,6 +200,16 @@ func TestWithTags(t *testing.T) {
 			assert.Equal(t, []string{"http", "notice_only", "security"}, failErr.Tags)
 		}
 	})
+
+	t.Run("duplicated", func(t *testing.T) {
+		err0 := errors.New("origin")
+
+		err1 := Wrap(err0, WithTags("http", "http"))
+		err2 := Wrap(err1, WithTags("security", "http"))
+
+		failErr := Unwrap(err2)
+		assert.Equal(t, []string{"http", "security"}, failErr.Tags)
+	})
 }
 
 func TestWithParams(t *testing.T) {
@@ -354,6 +364,17 @@ func TestWrap(t *testing.T) {
 	})
 }
 
+func TestWrapSkip(t *testing.T) {
+	assert.Nil(t, WrapSkip(nil, 1))
+
+	failErr := Unwrap(wrapOnBehalf(errors.New("origin"), WithCode(400)))
+	assert.Equal(t, 400, failErr.Code)
+	assert.Equal(t, "TestWrapSkip", failErr.StackTrace[0].Func)
+
+	failErr = Unwrap(WrapSkip(errors.New("origin"), 0))
+	assert.Equal(t, "TestWrapSkip", failErr.StackTrace[0].Func)
+}
+
 func TestAll(t *testing.T) {
 	t.Run("e-p-p-f", func(t *testing.T) {
 		failErr := Unwrap(errFunc0e1p2p3f())
@@ -444,6 +465,10 @@ func wrapOrigin(err error) error {
 	return Wrap(err)
 }
 
+func wrapOnBehalf(err error, annotators ...Annotator) error {
+	return WrapSkip(err, 1, annotators...)
+}
+
 func funcNamesFromStackTrace(stackTrace StackTrace) (funcNames []string) {
 	for _, frame := range stackTrace {
 		funcNames = append(funcNames, frame.Func)
@@ -507,3 +532,11 @@ func errFunc0e1p2p3pg4p() error {
 func errFunc0e1p2p3f4p() error {
 	return pkgerrors.Wrap(errFunc0e1p2p3f(), "4p")
 }
+
+func TestError_Unwrap(t *testing.T) {
+	err := errFunc0e()
+	failErr := Wrap(err, WithMessage("wrapped"))
+	if !errors.Is(failErr, err) {
+		t.Errorf("underlying error should be %v", err)
+	}
+}
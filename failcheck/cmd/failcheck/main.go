@@ -0,0 +1,16 @@
+// Command failcheck reports unwrapped and mis-annotated errors of fail.
+//
+// It can be run standalone, or via go vet:
+//
+//	failcheck ./...
+//	go vet -vettool=$(which failcheck) ./...
+package main
+
+import (
+	"github.com/srvc/fail/v4/failcheck"
+	"golang.org/x/tools/go/analysis/singlechecker"
+)
+
+func main() {
+	singlechecker.Main(failcheck.Analyzer)
+}
@@ -0,0 +1,432 @@
+// Package failcheck defines an analyzer that reports unwrapped and mis-annotated errors of fail.
+//
+// It reports:
+//
+//   - errors returned from functions of external packages without fail.Wrap
+//   - errors wrapped with fail.Wrap more than once in the same function
+//   - format strings of fail.WithMessagef and fail.Errorf that mismatch their arguments
+//   - calls of fail.New with non-constant strings, where fail.Errorf is meant
+//
+// Packages in the same module as the analyzed package are not external.
+// Additional internal packages can be specified with the -internal flag.
+package failcheck
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"regexp"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+const doc = `report unwrapped and mis-annotated errors of fail
+
+The failcheck analyzer reports errors returned from external packages
+without fail.Wrap, errors wrapped more than once in the same function,
+mismatched format strings of fail.WithMessagef and fail.Errorf,
+and fail.New called with non-constant strings.`
+
+// Analyzer reports unwrapped and mis-annotated errors
+var Analyzer = &analysis.Analyzer{
+	Name:     "failcheck",
+	Doc:      doc,
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+var internalPrefixes string
+
+func init() {
+	Analyzer.Flags.StringVar(&internalPrefixes, "internal", "", "comma-separated import path prefixes of packages whose errors need not be wrapped")
+}
+
+var failPkgPathRegexp = regexp.MustCompile(`^github\.com/srvc/fail(/v\d+)?$`)
+
+// exemptPkgPaths are packages whose functions create new errors rather than return errors from elsewhere
+var exemptPkgPaths = map[string]bool{
+	"errors": true,
+	"fmt":    true,
+}
+
+var errorType = types.Universe.Lookup("error").Type()
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	nodeFilter := []ast.Node{
+		(*ast.FuncDecl)(nil),
+		(*ast.FuncLit)(nil),
+		(*ast.CallExpr)(nil),
+	}
+
+	inspect.Preorder(nodeFilter, func(n ast.Node) {
+		switch n := n.(type) {
+		case *ast.FuncDecl:
+			if n.Body == nil {
+				return
+			}
+			if obj, ok := pass.TypesInfo.Defs[n.Name].(*types.Func); ok {
+				checkFunc(pass, obj.Type().(*types.Signature), n.Body)
+			}
+		case *ast.FuncLit:
+			if sig, ok := pass.TypesInfo.TypeOf(n).(*types.Signature); ok {
+				checkFunc(pass, sig, n.Body)
+			}
+		case *ast.CallExpr:
+			checkCall(pass, n)
+		}
+	})
+
+	return nil, nil
+}
+
+// origin is where a value of a variable comes from
+type origin int
+
+const (
+	originOther origin = iota
+	originExternal
+	originWrap
+)
+
+// assignment is an assignment to a variable in a function
+type assignment struct {
+	pos    token.Pos
+	origin origin
+	callee string
+}
+
+// funcChecker checks the body of a function, excluding nested function literals
+type funcChecker struct {
+	pass        *analysis.Pass
+	assignments map[types.Object][]assignment
+}
+
+// checkFunc reports unwrapped errors and double-wrapping in the function body
+func checkFunc(pass *analysis.Pass, sig *types.Signature, body *ast.BlockStmt) {
+	c := &funcChecker{
+		pass:        pass,
+		assignments: map[types.Object][]assignment{},
+	}
+
+	var returns []*ast.ReturnStmt
+	var wraps []*ast.CallExpr
+
+	inspectFuncBody(body, func(n ast.Node) {
+		switch n := n.(type) {
+		case *ast.AssignStmt:
+			c.recordAssign(n.End(), n.Lhs, n.Rhs)
+		case *ast.ValueSpec:
+			lhs := make([]ast.Expr, len(n.Names))
+			for i, name := range n.Names {
+				lhs[i] = name
+			}
+			c.recordAssign(n.End(), lhs, n.Values)
+		case *ast.ReturnStmt:
+			returns = append(returns, n)
+		case *ast.CallExpr:
+			if isFailWrap(pass, n) {
+				wraps = append(wraps, n)
+			}
+		}
+	})
+
+	for _, call := range wraps {
+		c.checkDoubleWrap(call)
+	}
+
+	results := sig.Results()
+	if results.Len() == 0 || !types.Identical(results.At(results.Len()-1).Type(), errorType) {
+		return
+	}
+	for _, ret := range returns {
+		c.checkReturn(ret, results.Len())
+	}
+}
+
+// inspectFuncBody calls f for each node in the body, without descending into function literals
+func inspectFuncBody(body *ast.BlockStmt, f func(ast.Node)) {
+	ast.Inspect(body, func(n ast.Node) bool {
+		if n == nil {
+			return false
+		}
+		if _, ok := n.(*ast.FuncLit); ok {
+			return false
+		}
+		f(n)
+		return true
+	})
+}
+
+// recordAssign records assignments of the statement, which take effect at the end position
+func (c *funcChecker) recordAssign(end token.Pos, lhs, rhs []ast.Expr) {
+	record := func(expr ast.Expr, value ast.Expr) {
+		id, ok := expr.(*ast.Ident)
+		if !ok || id.Name == "_" {
+			return
+		}
+		obj := c.pass.TypesInfo.ObjectOf(id)
+		if obj == nil || !types.Identical(obj.Type(), errorType) {
+			return
+		}
+		o, callee := c.originOf(value)
+		c.assignments[obj] = append(c.assignments[obj], assignment{pos: end, origin: o, callee: callee})
+	}
+
+	switch {
+	case len(lhs) == len(rhs):
+		for i := range lhs {
+			record(lhs[i], rhs[i])
+		}
+	case len(rhs) == 1:
+		for i := range lhs {
+			record(lhs[i], rhs[0])
+		}
+	}
+}
+
+// originOf returns where the value of the expression comes from
+func (c *funcChecker) originOf(expr ast.Expr) (origin, string) {
+	call, ok := ast.Unparen(expr).(*ast.CallExpr)
+	if !ok {
+		return originOther, ""
+	}
+	if isFailWrap(c.pass, call) {
+		return originWrap, ""
+	}
+	if fn, ok := typeutil.Callee(c.pass.TypesInfo, call).(*types.Func); ok && isExternal(c.pass, fn.Pkg()) {
+		return originExternal, calleeName(fn)
+	}
+	return originOther, ""
+}
+
+// lastAssignment returns the last assignment to the variable before the position
+func (c *funcChecker) lastAssignment(obj types.Object, pos token.Pos) (assignment, bool) {
+	var last assignment
+	found := false
+	for _, a := range c.assignments[obj] {
+		if a.pos < pos && (!found || a.pos > last.pos) {
+			last = a
+			found = true
+		}
+	}
+	return last, found
+}
+
+// checkReturn reports an error returned from an external package without fail.Wrap
+func (c *funcChecker) checkReturn(ret *ast.ReturnStmt, nresults int) {
+	if len(ret.Results) == 0 {
+		return
+	}
+
+	expr := ast.Unparen(ret.Results[len(ret.Results)-1])
+	if len(ret.Results) == 1 && nresults > 1 {
+		// return f() with multiple results
+		expr = ast.Unparen(ret.Results[0])
+	}
+
+	switch expr := expr.(type) {
+	case *ast.CallExpr:
+		if o, callee := c.originOf(expr); o == originExternal {
+			c.pass.Reportf(expr.Pos(), "error returned from %s is not wrapped with fail.Wrap", callee)
+		}
+	case *ast.Ident:
+		obj := c.pass.TypesInfo.ObjectOf(expr)
+		if obj == nil {
+			return
+		}
+		if a, ok := c.lastAssignment(obj, ret.Pos()); ok && a.origin == originExternal {
+			c.pass.Reportf(expr.Pos(), "error returned from %s is not wrapped with fail.Wrap", a.callee)
+		}
+	}
+}
+
+// checkDoubleWrap reports fail.Wrap of an error that is already wrapped in the same function
+func (c *funcChecker) checkDoubleWrap(call *ast.CallExpr) {
+	arg := wrappedArg(c.pass, call)
+	if arg == nil {
+		return
+	}
+
+	switch arg := ast.Unparen(arg).(type) {
+	case *ast.CallExpr:
+		if isFailWrap(c.pass, arg) {
+			c.pass.Reportf(call.Pos(), "error is wrapped with fail.Wrap twice")
+		}
+	case *ast.Ident:
+		obj := c.pass.TypesInfo.ObjectOf(arg)
+		if obj == nil {
+			return
+		}
+		if a, ok := c.lastAssignment(obj, call.Pos()); ok && a.origin == originWrap {
+			c.pass.Reportf(call.Pos(), "error %s is already wrapped with fail.Wrap in this function", arg.Name)
+		}
+	}
+}
+
+// checkCall checks format strings and arguments of functions of fail
+func checkCall(pass *analysis.Pass, call *ast.CallExpr) {
+	fn, ok := typeutil.Callee(pass.TypesInfo, call).(*types.Func)
+	if !ok || !isFailPkg(fn.Pkg()) {
+		return
+	}
+
+	sig := fn.Type().(*types.Signature)
+	switch {
+	case sig.Recv() == nil && fn.Name() == "New":
+		checkNew(pass, call)
+	case sig.Recv() == nil && fn.Name() == "WithMessagef":
+		checkFormat(pass, call, 0, "fail.WithMessagef", false)
+	case sig.Recv() == nil && fn.Name() == "Errorf":
+		checkFormat(pass, call, 0, "fail.Errorf", true)
+	case sig.Recv() != nil && fn.Name() == "Errorf":
+		checkFormat(pass, call, 0, "Kind.Errorf", true)
+	case sig.Recv() != nil && fn.Name() == "ErrorfSkip":
+		checkFormat(pass, call, 1, "Kind.ErrorfSkip", true)
+	}
+}
+
+// checkNew reports fail.New with a non-constant string
+func checkNew(pass *analysis.Pass, call *ast.CallExpr) {
+	if len(call.Args) != 1 {
+		return
+	}
+	arg := call.Args[0]
+	if tv, ok := pass.TypesInfo.Types[arg]; ok && tv.Value != nil {
+		return
+	}
+
+	diag := analysis.Diagnostic{
+		Pos:     call.Pos(),
+		End:     call.End(),
+		Message: "fail.New is called with a non-constant string; use fail.Errorf instead",
+	}
+
+	// fail.New(fmt.Sprintf(format, args...)) -> fail.Errorf(format, args...)
+	if sprintf, ok := ast.Unparen(arg).(*ast.CallExpr); ok && isFunc(pass, sprintf, "fmt", "Sprintf") {
+		if sel, ok := ast.Unparen(call.Fun).(*ast.SelectorExpr); ok {
+			diag.SuggestedFixes = []analysis.SuggestedFix{{
+				Message: "Use fail.Errorf",
+				TextEdits: []analysis.TextEdit{
+					{Pos: sel.Sel.Pos(), End: sel.Sel.End(), NewText: []byte("Errorf")},
+					{Pos: arg.Pos(), End: sprintf.Lparen + 1, NewText: nil},
+					{Pos: sprintf.Rparen, End: arg.End(), NewText: nil},
+				},
+			}}
+		}
+	}
+
+	pass.Report(diag)
+}
+
+// checkFormat reports mismatches between the format string at the index of the arguments and the rest of them
+func checkFormat(pass *analysis.Pass, call *ast.CallExpr, index int, name string, allowWrap bool) {
+	if len(call.Args) <= index || call.Ellipsis.IsValid() {
+		return
+	}
+	tv, ok := pass.TypesInfo.Types[call.Args[index]]
+	if !ok || tv.Value == nil || tv.Value.Kind() != constant.String {
+		return
+	}
+
+	format := constant.StringVal(tv.Value)
+	args := call.Args[index+1:]
+
+	directives, err := parseFormat(format)
+	if err != nil {
+		pass.Reportf(call.Pos(), "%s format %q: %v", name, format, err)
+		return
+	}
+
+	argNum := 0
+	for _, d := range directives {
+		if d.verb == 'w' && !allowWrap {
+			pass.Reportf(call.Pos(), "%s does not support error-wrapping directive %%w", name)
+			return
+		}
+		argNum += d.stars
+		if d.verb == '%' {
+			continue
+		}
+		if argNum >= len(args) {
+			pass.Reportf(call.Pos(), "%s format %s reads arg #%d, but call has %d args", name, d.text, argNum+1, len(args))
+			return
+		}
+		if typ := pass.TypesInfo.TypeOf(args[argNum]); typ != nil && !matchVerb(d.verb, typ) {
+			pass.Reportf(args[argNum].Pos(), "%s format %s has arg %s of wrong type %s", name, d.text, types.ExprString(args[argNum]), typ)
+		}
+		argNum++
+	}
+
+	if argNum < len(args) {
+		pass.Reportf(call.Pos(), "%s call needs %d args but has %d args", name, argNum, len(args))
+	}
+}
+
+// isFailWrap reports whether the call is fail.Wrap, fail.WrapContext or Kind.Wrap
+func isFailWrap(pass *analysis.Pass, call *ast.CallExpr) bool {
+	fn, ok := typeutil.Callee(pass.TypesInfo, call).(*types.Func)
+	if !ok || !isFailPkg(fn.Pkg()) {
+		return false
+	}
+	return fn.Name() == "Wrap" || fn.Name() == "WrapContext"
+}
+
+// wrappedArg returns the error argument of a wrap call
+func wrappedArg(pass *analysis.Pass, call *ast.CallExpr) ast.Expr {
+	fn := typeutil.Callee(pass.TypesInfo, call).(*types.Func)
+	i := 0
+	if fn.Name() == "WrapContext" {
+		i = 1
+	}
+	if len(call.Args) <= i {
+		return nil
+	}
+	return call.Args[i]
+}
+
+// isFunc reports whether the call is a call of the package-level function
+func isFunc(pass *analysis.Pass, call *ast.CallExpr, pkgPath, name string) bool {
+	fn, ok := typeutil.Callee(pass.TypesInfo, call).(*types.Func)
+	return ok && fn.Pkg() != nil && fn.Pkg().Path() == pkgPath && fn.Name() == name
+}
+
+func isFailPkg(pkg *types.Package) bool {
+	return pkg != nil && failPkgPathRegexp.MatchString(pkg.Path())
+}
+
+// isExternal reports whether errors from the package should be wrapped
+func isExternal(pass *analysis.Pass, pkg *types.Package) bool {
+	if pkg == nil || pkg == pass.Pkg || isFailPkg(pkg) || exemptPkgPaths[pkg.Path()] {
+		return false
+	}
+
+	path := pkg.Path()
+	if pass.Module != nil && pass.Module.Path != "" {
+		if path == pass.Module.Path || strings.HasPrefix(path, pass.Module.Path+"/") {
+			return false
+		}
+	}
+	for _, prefix := range strings.Split(internalPrefixes, ",") {
+		if prefix != "" && strings.HasPrefix(path, prefix) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// calleeName returns a qualified name of the function, such as "io.ReadAll" or "(*os.File).Read"
+func calleeName(fn *types.Func) string {
+	sig := fn.Type().(*types.Signature)
+	if recv := sig.Recv(); recv != nil {
+		return "(" + types.TypeString(recv.Type(), (*types.Package).Name) + ")." + fn.Name()
+	}
+	return fn.Pkg().Name() + "." + fn.Name()
+}
@@ -0,0 +1,13 @@
+package failcheck_test
+
+import (
+	"testing"
+
+	"github.com/srvc/fail/v4/failcheck"
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestAnalyzer(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.RunWithSuggestedFixes(t, testdata, failcheck.Analyzer, "a")
+}
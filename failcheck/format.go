@@ -0,0 +1,235 @@
+package failcheck
+
+import (
+	"fmt"
+	"go/types"
+	"strings"
+	"unicode/utf8"
+)
+
+// directive is a formatting directive in a format string, such as "%-10d"
+type directive struct {
+	text  string
+	verb  rune
+	stars int
+}
+
+// parseFormat extracts the directives from a format string.
+// Explicit argument indexes are not supported.
+func parseFormat(format string) ([]directive, error) {
+	var directives []directive
+
+	for i := 0; i < len(format); {
+		if format[i] != '%' {
+			i++
+			continue
+		}
+
+		start := i
+		i++
+		d := directive{}
+
+		// flags, width and precision
+		for i < len(format) && strings.IndexByte("+-# 0123456789.*", format[i]) >= 0 {
+			if format[i] == '*' {
+				d.stars++
+			}
+			i++
+		}
+		if i < len(format) && format[i] == '[' {
+			return nil, fmt.Errorf("explicit argument indexes are not supported")
+		}
+		if i >= len(format) {
+			return nil, fmt.Errorf("missing verb at end of format string")
+		}
+
+		verb, size := utf8.DecodeRuneInString(format[i:])
+		i += size
+		d.verb = verb
+		d.text = format[start:i]
+
+		if verb != '%' && !strings.ContainsRune(knownVerbs, verb) {
+			return nil, fmt.Errorf("unrecognized verb %s", d.text)
+		}
+
+		directives = append(directives, d)
+	}
+
+	return directives, nil
+}
+
+const knownVerbs = "vTtbcdoOqxXUeEfFgGspw"
+
+// argTypes are the kinds of arguments accepted by a verb
+type argTypes int
+
+const (
+	argBool argTypes = 1 << iota
+	argInt
+	argRune
+	argString
+	argFloat
+	argComplex
+	argPointer
+	argError
+	anyType argTypes = -1
+)
+
+// verbArgTypes are the kinds of arguments accepted by each verb, following the printf check of go vet
+var verbArgTypes = map[rune]argTypes{
+	'b': argInt | argFloat | argComplex | argPointer,
+	'c': argRune | argInt,
+	'd': argInt | argPointer,
+	'e': argFloat | argComplex,
+	'E': argFloat | argComplex,
+	'f': argFloat | argComplex,
+	'F': argFloat | argComplex,
+	'g': argFloat | argComplex,
+	'G': argFloat | argComplex,
+	'o': argInt | argPointer,
+	'O': argInt | argPointer,
+	'p': argPointer,
+	'q': argRune | argInt | argString,
+	's': argString,
+	't': argBool,
+	'T': anyType,
+	'U': argRune | argInt,
+	'v': anyType,
+	'w': argError,
+	'x': argRune | argInt | argString | argPointer | argFloat | argComplex,
+	'X': argRune | argInt | argString | argPointer | argFloat | argComplex,
+}
+
+// matchVerb reports whether the type can be formatted with the verb, following the printf check of go vet.
+// Slices, arrays, maps and structs match if their elements do, and interface types always match.
+func matchVerb(verb rune, typ types.Type) bool {
+	t, ok := verbArgTypes[verb]
+	if !ok {
+		return true
+	}
+	return (&argMatcher{t: t, seen: map[types.Type]bool{}}).match(typ, true)
+}
+
+// argMatcher matches types against the kinds of arguments of a verb
+type argMatcher struct {
+	t    argTypes
+	seen map[types.Type]bool
+}
+
+func (m *argMatcher) match(typ types.Type, topLevel bool) bool {
+	if m.t == argError {
+		return types.ConvertibleTo(typ, errorType)
+	}
+	if m.t == anyType || isFormatter(typ) {
+		return true
+	}
+	if m.t&argString != 0 && isConvertibleToString(typ) {
+		return true
+	}
+	if _, ok := typ.(*types.TypeParam); ok {
+		return true
+	}
+
+	typ = typ.Underlying()
+	if m.seen[typ] {
+		return true
+	}
+	m.seen[typ] = true
+
+	switch typ := typ.(type) {
+	case *types.Signature:
+		return m.t == argPointer
+	case *types.Chan:
+		return m.t&argPointer != 0
+	case *types.Map:
+		if m.t == argPointer {
+			return true
+		}
+		return m.match(typ.Key(), false) && m.match(typ.Elem(), false)
+	case *types.Array:
+		if types.Identical(typ.Elem().Underlying(), types.Typ[types.Byte]) && m.t&argString != 0 {
+			return true
+		}
+		return m.match(typ.Elem(), false)
+	case *types.Slice:
+		if types.Identical(typ.Elem().Underlying(), types.Typ[types.Byte]) && m.t&argString != 0 {
+			return true
+		}
+		if m.t == argPointer {
+			return true
+		}
+		return m.match(typ.Elem(), false)
+	case *types.Pointer:
+		if m.t == argPointer {
+			return true
+		}
+		// a top-level pointer to a composite value prints as the value, such as &{...}
+		switch under := typ.Elem().Underlying().(type) {
+		case *types.Struct, *types.Array, *types.Slice, *types.Map:
+			return topLevel && m.match(under, false)
+		}
+		return m.t&argPointer != 0
+	case *types.Struct:
+		for i := 0; i < typ.NumFields(); i++ {
+			f := typ.Field(i)
+			if !m.match(f.Type(), false) {
+				return false
+			}
+			// fmt can't call methods of unexported fields
+			if m.t&argString != 0 && !f.Exported() && isConvertibleToString(f.Type()) {
+				return false
+			}
+		}
+		return true
+	case *types.Interface:
+		return true
+	case *types.Basic:
+		switch {
+		case typ.Kind() == types.UntypedNil:
+			return false
+		case typ.Kind() == types.UnsafePointer:
+			return m.t&(argPointer|argInt) != 0
+		case typ.Kind() == types.UntypedRune:
+			return m.t&(argRune|argInt) != 0
+		case typ.Kind() == types.Invalid:
+			return true
+		case typ.Info()&types.IsBoolean != 0:
+			return m.t&argBool != 0
+		case typ.Info()&types.IsInteger != 0:
+			return m.t&argInt != 0
+		case typ.Info()&types.IsFloat != 0:
+			return m.t&argFloat != 0
+		case typ.Info()&types.IsComplex != 0:
+			return m.t&argComplex != 0
+		case typ.Info()&types.IsString != 0:
+			return m.t&argString != 0
+		}
+	}
+	return false
+}
+
+// isFormatter reports whether the type has the Format method of fmt.Formatter,
+// or is an interface whose values may have it
+func isFormatter(typ types.Type) bool {
+	if _, ok := typ.(*types.TypeParam); !ok {
+		if _, ok := typ.Underlying().(*types.Interface); ok {
+			return true
+		}
+	}
+	return hasMethod(typ, "Format")
+}
+
+// isConvertibleToString reports whether the value may be formatted by its Error or String method
+func isConvertibleToString(typ types.Type) bool {
+	if b, ok := typ.(*types.Basic); ok && b.Kind() == types.UntypedNil {
+		return false
+	}
+	return types.ConvertibleTo(typ, errorType) || hasMethod(typ, "String")
+}
+
+// hasMethod reports whether the method set of the type has the method
+func hasMethod(typ types.Type, method string) bool {
+	obj, _, _ := types.LookupFieldOrMethod(typ, false, nil, method)
+	_, ok := obj.(*types.Func)
+	return ok
+}
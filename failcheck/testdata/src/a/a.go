@@ -0,0 +1,156 @@
+package a
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"ext"
+
+	"github.com/srvc/fail/v4"
+)
+
+var errKind = fail.Define("kind")
+
+// unwrapped
+
+func returnCall() error {
+	return ext.Do() // want `error returned from ext.Do is not wrapped with fail.Wrap`
+}
+
+func returnVar() error {
+	err := ext.Do()
+	if err != nil {
+		return err // want `error returned from ext.Do is not wrapped with fail.Wrap`
+	}
+	return nil
+}
+
+func returnIfInit() error {
+	if err := ext.Do(); err != nil {
+		return err // want `error returned from ext.Do is not wrapped with fail.Wrap`
+	}
+	return nil
+}
+
+func returnTuple() (int, error) {
+	n, err := ext.Find()
+	if err != nil {
+		return 0, err // want `error returned from ext.Find is not wrapped with fail.Wrap`
+	}
+	return n, nil
+}
+
+func returnMultipleResults() (int, error) {
+	return ext.Find() // want `error returned from ext.Find is not wrapped with fail.Wrap`
+}
+
+func returnMethod(c *ext.Client) error {
+	_, err := c.Get()
+	return err // want `error returned from \(\*ext.Client\).Get is not wrapped with fail.Wrap`
+}
+
+func returnFuncLit() func() error {
+	return func() error {
+		return ext.Do() // want `error returned from ext.Do is not wrapped with fail.Wrap`
+	}
+}
+
+func returnWrapped() error {
+	err := ext.Do()
+	if err != nil {
+		return fail.Wrap(err)
+	}
+	return nil
+}
+
+func returnReassigned() error {
+	err := ext.Do()
+	err = fail.Wrap(err)
+	return err
+}
+
+func returnLocal() error {
+	return local()
+}
+
+func returnStd() error {
+	if true {
+		return errors.New("error")
+	}
+	return fmt.Errorf("error")
+}
+
+func local() error {
+	return nil
+}
+
+// double wrapping
+
+func wrapNested() error {
+	return fail.Wrap(fail.Wrap(ext.Do())) // want `error is wrapped with fail.Wrap twice`
+}
+
+func wrapTwice(ctx context.Context) error {
+	err := fail.Wrap(ext.Do())
+	if err != nil {
+		return fail.WrapContext(ctx, err) // want `error err is already wrapped with fail.Wrap in this function`
+	}
+	return nil
+}
+
+func wrapKindTwice() error {
+	err := errKind.Wrap(ext.Do())
+	return fail.Wrap(err) // want `error err is already wrapped with fail.Wrap in this function`
+}
+
+func wrapInDifferentFuncs() error {
+	err := fail.Wrap(ext.Do())
+	f := func() error {
+		return fail.Wrap(err)
+	}
+	return f()
+}
+
+// format strings
+
+type stringer struct{}
+
+func (stringer) String() string { return "" }
+
+func formats(name string, n int, err error) {
+	fail.WithMessagef("%s: %d", name, n)
+	fail.WithMessagef("%v %s %%", err, stringer{})
+	fail.WithMessagef("%*d", n, n)
+	fail.Errorf("%w", err)
+	errKind.Errorf("%s", name)
+	errKind.ErrorfSkip(1, "%s", name)
+	fail.WithMessagef("%s %d %v", []string{name}, []int{n}, []error{err})
+	fail.WithMessagef("%s %x %d", []byte(name), [4]byte{}, map[int]int{})
+	fail.WithMessagef("%d %s %p", &struct{ N int }{n}, struct{ Name string }{name}, &n)
+	fail.WithMessagef("%c %q %U %x", 'a', 'a', n, 1.5)
+	fail.WithMessagef("%f %g", 1.5, complex(1, 2))
+
+	fail.WithMessagef("%s: %d", name)      // want `fail.WithMessagef format %d reads arg #2, but call has 1 args`
+	fail.WithMessagef("%s", name, n)       // want `fail.WithMessagef call needs 1 args but has 2 args`
+	fail.WithMessagef("%d", name)          // want `fail.WithMessagef format %d has arg name of wrong type string`
+	fail.WithMessagef("%w", err)           // want `fail.WithMessagef does not support error-wrapping directive %w`
+	fail.WithMessagef("%z", name)          // want `fail.WithMessagef format "%z": unrecognized verb %z`
+	fail.Errorf("user %d not found", "id") // want `fail.Errorf format %d has arg "id" of wrong type string`
+	fail.WithMessagef("%f", n)             // want `fail.WithMessagef format %f has arg n of wrong type int`
+	fail.WithMessagef("%d", []string{})    // want `fail.WithMessagef format %d has arg \[\]string{} of wrong type \[\]string`
+	fail.WithMessagef("%s", n)             // want `fail.WithMessagef format %s has arg n of wrong type int`
+	fail.WithMessagef("%t", &n)            // want `fail.WithMessagef format %t has arg &n of wrong type \*int`
+	errKind.Errorf("user %s not found")    // want `Kind.Errorf format %s reads arg #1, but call has 0 args`
+	errKind.ErrorfSkip(1, "user %d", name) // want `Kind.ErrorfSkip format %d has arg name of wrong type string`
+}
+
+// fail.New
+
+func news(name string) {
+	fail.New("error")
+	fail.New("error: " + "constant")
+
+	fail.New(name)                                   // want `fail.New is called with a non-constant string; use fail.Errorf instead`
+	fail.New(fmt.Sprintf("user %s not found", name)) // want `fail.New is called with a non-constant string; use fail.Errorf instead`
+}
@@ -0,0 +1,10 @@
+// Package ext is an external package for tests
+package ext
+
+type Client struct{}
+
+func (c *Client) Get() (string, error) { return "", nil }
+
+func Do() error { return nil }
+
+func Find() (int, error) { return 0, nil }
@@ -0,0 +1,26 @@
+// Package fail is a stub of github.com/srvc/fail/v4 for tests
+package fail
+
+import "context"
+
+type Annotator func(*Error)
+
+type Error struct{ Err error }
+
+func (e *Error) Error() string { return e.Err.Error() }
+
+type Kind struct{}
+
+func (k *Kind) Error() string                                                   { return "" }
+func (k *Kind) New(text string) error                                           { return nil }
+func (k *Kind) Errorf(format string, args ...interface{}) error                 { return nil }
+func (k *Kind) ErrorfSkip(skip int, format string, args ...interface{}) error   { return nil }
+func (k *Kind) Wrap(err error, annotators ...Annotator) error                   { return err }
+func Define(id string, annotators ...Annotator) *Kind                           { return &Kind{} }
+func New(text string) error                                                     { return nil }
+func Errorf(format string, args ...interface{}) error                           { return nil }
+func Wrap(err error, annotators ...Annotator) error                             { return err }
+func WrapContext(ctx context.Context, err error, annotators ...Annotator) error { return err }
+func WithMessage(msg string) Annotator                                          { return nil }
+func WithMessagef(msg string, args ...interface{}) Annotator                    { return nil }
+func WithCode(code interface{}) Annotator                                       { return nil }
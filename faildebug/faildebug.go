@@ -0,0 +1,122 @@
+// Package faildebug keeps recent errors in memory and serves them over HTTP for debugging.
+//
+// Importing the package registers the handler of Default at /debug/errors
+// on http.DefaultServeMux, in the same way as expvar and net/http/pprof.
+//
+//	import _ "github.com/srvc/fail/v4/faildebug"
+//
+//	faildebug.Report(err)
+package faildebug
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/srvc/fail/v4"
+)
+
+// DefaultSize is the number of errors kept by Default
+const DefaultSize = 100
+
+// Default is the Recorder served at /debug/errors
+var Default = NewRecorder(DefaultSize)
+
+func init() {
+	http.Handle("/debug/errors", Default.Handler())
+}
+
+// Report records the error to Default
+func Report(err error) {
+	Default.Report(err)
+}
+
+// Entry is an error recorded by Recorder
+type Entry struct {
+	Time        time.Time   `json:"time"`
+	Fingerprint string      `json:"fingerprint"`
+	Err         *fail.Error `json:"error"`
+}
+
+// Recorder is a bounded buffer of recent errors.
+// It overwrites the oldest error when it's full, and is safe for concurrent use.
+type Recorder struct {
+	now func() time.Time
+
+	mu      sync.RWMutex
+	entries []Entry
+	next    int
+	full    bool
+}
+
+// NewRecorder creates a Recorder that keeps the number of recent errors
+func NewRecorder(size int) *Recorder {
+	if size < 1 {
+		size = 1
+	}
+	return &Recorder{
+		now:     time.Now,
+		entries: make([]Entry, size),
+	}
+}
+
+// Report records the error.
+// Errors not derived from fail are recorded as *fail.Error without a stack trace.
+func (r *Recorder) Report(err error) {
+	if err == nil {
+		return
+	}
+
+	failErr := fail.Unwrap(err)
+	if failErr == nil {
+		failErr = &fail.Error{Err: err}
+	}
+	e := Entry{
+		Time:        r.now(),
+		Fingerprint: failErr.Fingerprint(),
+		Err:         deepCopy(failErr),
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.entries[r.next] = e
+	r.next = (r.next + 1) % len(r.entries)
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+// deepCopy copies the error with the params, the tags and the labels,
+// so that the recorded error doesn't change when the original one is annotated later
+func deepCopy(err *fail.Error) *fail.Error {
+	c := err.Copy()
+	c.Params = err.Params.Clone()
+	if err.Tags != nil {
+		c.Tags = append([]string{}, err.Tags...)
+	}
+	if err.Labels != nil {
+		c.Labels = make(map[string]string, len(err.Labels))
+		for k, v := range err.Labels {
+			c.Labels[k] = v
+		}
+	}
+	return c
+}
+
+// Entries returns the recorded errors from newest to oldest
+func (r *Recorder) Entries() []Entry {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	n := r.next
+	if r.full {
+		n = len(r.entries)
+	}
+
+	entries := make([]Entry, n)
+	for i := range entries {
+		entries[i] = r.entries[(r.next-1-i+len(r.entries))%len(r.entries)]
+	}
+	return entries
+}
@@ -0,0 +1,103 @@
+package faildebug
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/srvc/fail/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestRecorder(size int) *Recorder {
+	r := NewRecorder(size)
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	r.now = func() time.Time {
+		now = now.Add(time.Second)
+		return now
+	}
+	return r
+}
+
+func messages(entries []Entry) []string {
+	msgs := make([]string, len(entries))
+	for i, e := range entries {
+		msgs[i] = e.Err.Error()
+	}
+	return msgs
+}
+
+func TestRecorder(t *testing.T) {
+	t.Run("empty", func(t *testing.T) {
+		assert.Empty(t, newTestRecorder(3).Entries())
+	})
+
+	t.Run("not full", func(t *testing.T) {
+		r := newTestRecorder(3)
+		r.Report(fail.New("error 1"))
+		r.Report(fail.New("error 2"))
+
+		entries := r.Entries()
+		assert.Equal(t, []string{"error 2", "error 1"}, messages(entries))
+		assert.Equal(t, time.Date(2020, 1, 1, 0, 0, 2, 0, time.UTC), entries[0].Time)
+		assert.Equal(t, entries[0].Err.Fingerprint(), entries[0].Fingerprint)
+	})
+
+	t.Run("overwrite", func(t *testing.T) {
+		r := newTestRecorder(3)
+		for i := 1; i <= 5; i++ {
+			r.Report(fail.New(fmt.Sprintf("error %d", i)))
+		}
+		assert.Equal(t, []string{"error 5", "error 4", "error 3"}, messages(r.Entries()))
+	})
+
+	t.Run("not fail error", func(t *testing.T) {
+		r := newTestRecorder(3)
+		r.Report(errors.New("origin"))
+		r.Report(nil)
+
+		entries := r.Entries()
+		assert.Equal(t, []string{"origin"}, messages(entries))
+		assert.Empty(t, entries[0].Err.StackTrace)
+	})
+
+	t.Run("deep copy", func(t *testing.T) {
+		err := &fail.Error{
+			Err:    errors.New("origin"),
+			Tags:   make([]string, 1, 2),
+			Params: fail.H{"user": fail.H{"id": 1}, "ids": []interface{}{1}},
+			Labels: map[string]string{"handler": "users"},
+		}
+		err.Tags[0] = "http"
+
+		r := newTestRecorder(3)
+		r.Report(err)
+
+		err.Params["user"].(fail.H)["id"] = 2
+		err.Params["ids"].([]interface{})[0] = 2
+		err.Tags[0] = "db"
+		err.Labels["handler"] = "items"
+
+		recorded := r.Entries()[0].Err
+		assert.Equal(t, fail.H{"user": fail.H{"id": 1}, "ids": []interface{}{1}}, recorded.Params)
+		assert.Equal(t, []string{"http"}, recorded.Tags)
+		assert.Equal(t, map[string]string{"handler": "users"}, recorded.Labels)
+	})
+
+	t.Run("concurrent", func(t *testing.T) {
+		r := NewRecorder(10)
+		var wg sync.WaitGroup
+		for i := 0; i < 100; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				r.Report(fail.New("error"))
+				r.Entries()
+			}()
+		}
+		wg.Wait()
+		assert.Len(t, r.Entries(), 10)
+	})
+}
@@ -0,0 +1,118 @@
+package faildebug
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+	"strings"
+
+	"github.com/srvc/fail/v4"
+)
+
+// Handler returns an http.Handler that serves the recorded errors from newest to oldest.
+//
+// It serves JSON if the "format" query is "json" or the request accepts application/json,
+// and HTML otherwise. The errors are filtered by the "code", "tag" and "match" queries if given,
+// where "tag" is a tag or a fail.TagQuery such as "db && !timeout",
+// and "match" is the text of fail.ParseMatcher such as "code:NotFound || root:io.EOF".
+func (r *Recorder) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		q := req.URL.Query()
+		entries := filterEntries(r.Entries(), q.Get("code"), q.Get("tag"), q.Get("match"))
+
+		if q.Get("format") == "json" || strings.Contains(req.Header.Get("Accept"), "application/json") {
+			w.Header().Set("Content-Type", "application/json; charset=utf-8")
+			if entries == nil {
+				entries = []Entry{}
+			}
+			json.NewEncoder(w).Encode(entries)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		indexTemplate.Execute(w, map[string]interface{}{
+			"Entries": entries,
+			"Code":    q.Get("code"),
+			"Tag":     q.Get("tag"),
+			"Match":   q.Get("match"),
+		})
+	})
+}
+
+// filterEntries returns the entries that have the code and match the tag query and the matcher.
+// Empty code, tag or match matches every entry, and an invalid tag query or matcher matches no entry.
+func filterEntries(entries []Entry, code, tag, match string) []Entry {
+	var query *fail.TagQuery
+	if tag != "" {
+		q, err := fail.ParseTagQuery(tag)
+		if err != nil {
+			return nil
+		}
+		query = q
+	}
+	var matcher fail.Matcher
+	if match != "" {
+		m, err := fail.ParseMatcher(match)
+		if err != nil {
+			return nil
+		}
+		matcher = m
+	}
+
+	var filtered []Entry
+	for _, e := range entries {
+		if code != "" && (e.Err.Code == nil || fmt.Sprint(e.Err.Code) != code) {
+			continue
+		}
+		if query != nil && !query.Match(e.Err.Tags) {
+			continue
+		}
+		if matcher != nil && !matcher(e.Err) {
+			continue
+		}
+		filtered = append(filtered, e)
+	}
+	return filtered
+}
+
+var indexTemplate = template.Must(template.New("index").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>/debug/errors</title>
+<style>
+body { font-family: -apple-system, BlinkMacSystemFont, sans-serif; margin: 1em 2em; color: #222; }
+table { border-collapse: collapse; width: 100%; }
+th, td { border: 1px solid #ddd; padding: .3em .8em; text-align: left; vertical-align: top; }
+th { background: #f5f5f5; }
+code, pre { font-family: SFMono-Regular, Menlo, monospace; font-size: .9em; }
+pre { margin: .5em 0 0; }
+</style>
+</head>
+<body>
+<h1>/debug/errors</h1>
+<form>
+<label>code <input name="code" value="{{.Code}}"></label>
+<label>tag <input name="tag" value="{{.Tag}}"></label>
+<label>match <input name="match" value="{{.Match}}"></label>
+<button>Filter</button>
+<a href="?format=json&amp;code={{.Code}}&amp;tag={{.Tag}}&amp;match={{.Match}}">JSON</a>
+</form>
+<p>{{len .Entries}} errors</p>
+<table>
+<tr><th>time</th><th>fingerprint</th><th>code</th><th>tags</th><th>error</th></tr>
+{{- range .Entries}}
+<tr>
+<td>{{.Time.Format "2006-01-02T15:04:05.000Z07:00"}}</td>
+<td><code>{{.Fingerprint}}</code></td>
+<td>{{with .Err.Code}}<code>{{.}}</code>{{end}}</td>
+<td>{{range $i, $t := .Err.Tags}}{{if $i}}, {{end}}<a href="?tag={{$t}}">{{$t}}</a>{{end}}</td>
+<td><details><summary>{{.Err.Error}}</summary><pre>{{printf "%+v" .Err}}</pre></details></td>
+</tr>
+{{- end}}
+</table>
+</body>
+</html>
+`))
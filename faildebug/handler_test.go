@@ -0,0 +1,92 @@
+package faildebug
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/srvc/fail/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecorder_Handler(t *testing.T) {
+	r := newTestRecorder(10)
+	r.Report(fail.Wrap(fail.New("not found"), fail.WithCode(404), fail.WithTags("user")))
+	r.Report(fail.Wrap(fail.New("<b>internal</b>"), fail.WithCode(500), fail.WithTags("db")))
+	r.Report(fail.Wrap(fail.New("conflict"), fail.WithCode(409), fail.WithTags("user")))
+
+	get := func(url string, header http.Header) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", url, nil)
+		for k, v := range header {
+			req.Header[k] = v
+		}
+		w := httptest.NewRecorder()
+		r.Handler().ServeHTTP(w, req)
+		return w
+	}
+
+	jsonMessages := func(t *testing.T, w *httptest.ResponseRecorder) []string {
+		assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+		var entries []struct {
+			Fingerprint string `json:"fingerprint"`
+			Err         struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &entries))
+		msgs := make([]string, len(entries))
+		for i, e := range entries {
+			assert.Len(t, e.Fingerprint, 16)
+			msgs[i] = e.Err.Message
+		}
+		return msgs
+	}
+
+	cases := []struct {
+		test   string
+		url    string
+		header http.Header
+		want   []string
+	}{
+		{test: "json", url: "/debug/errors?format=json", want: []string{"conflict", "<b>internal</b>", "not found"}},
+		{test: "accept", url: "/debug/errors", header: http.Header{"Accept": {"application/json"}}, want: []string{"conflict", "<b>internal</b>", "not found"}},
+		{test: "code", url: "/debug/errors?format=json&code=404", want: []string{"not found"}},
+		{test: "tag", url: "/debug/errors?format=json&tag=user", want: []string{"conflict", "not found"}},
+		{test: "code and tag", url: "/debug/errors?format=json&code=500&tag=user", want: []string{}},
+		{test: "tag query", url: "/debug/errors?format=json&tag=" + url.QueryEscape("db || !user"), want: []string{"<b>internal</b>"}},
+		{test: "invalid tag query", url: "/debug/errors?format=json&tag=" + url.QueryEscape("db ||"), want: []string{}},
+		{test: "match", url: "/debug/errors?format=json&match=" + url.QueryEscape("code:404 || tag:db"), want: []string{"<b>internal</b>", "not found"}},
+		{test: "invalid match", url: "/debug/errors?format=json&match=unknown", want: []string{}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.test, func(t *testing.T) {
+			assert.Equal(t, c.want, jsonMessages(t, get(c.url, c.header)))
+		})
+	}
+
+	t.Run("html", func(t *testing.T) {
+		w := get("/debug/errors?tag=user", nil)
+
+		assert.Equal(t, "text/html; charset=utf-8", w.Header().Get("Content-Type"))
+		body := w.Body.String()
+		assert.Contains(t, body, "<p>2 errors</p>")
+		assert.Contains(t, body, "<summary>conflict</summary>")
+		assert.Contains(t, body, `<input name="tag" value="user">`)
+		assert.NotContains(t, body, "internal")
+	})
+
+	t.Run("html escape", func(t *testing.T) {
+		body := get("/debug/errors", nil).Body.String()
+		assert.Contains(t, body, "<summary>&lt;b&gt;internal&lt;/b&gt;</summary>")
+		assert.NotContains(t, body, "<b>internal</b>")
+	})
+
+	t.Run("default", func(t *testing.T) {
+		handler, pattern := http.DefaultServeMux.Handler(httptest.NewRequest("GET", "/debug/errors", nil))
+		assert.Equal(t, "/debug/errors", pattern)
+		assert.NotNil(t, handler)
+	})
+}
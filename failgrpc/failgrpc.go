@@ -0,0 +1,110 @@
+// Package failgrpc converts errors of fail into gRPC statuses.
+package failgrpc
+
+import (
+	"context"
+	"sync"
+
+	"github.com/srvc/fail/v4"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+type codeRule struct {
+	matcher fail.Matcher
+	code    codes.Code
+}
+
+var (
+	codeRulesMu sync.RWMutex
+	codeRules   []codeRule
+)
+
+// RegisterCode maps errors that match the matcher to the gRPC code.
+// Rules are consulted by Code in the order they were registered, before the code of the error.
+//
+//	failgrpc.RegisterCode(fail.MustParseMatcher("root:io.EOF"), codes.NotFound)
+func RegisterCode(m fail.Matcher, code codes.Code) {
+	codeRulesMu.Lock()
+	defer codeRulesMu.Unlock()
+	codeRules = append(codeRules, codeRule{matcher: m, code: code})
+}
+
+// Code returns the gRPC code of the error.
+// It's the code of the first rule registered by RegisterCode that matches the error,
+// the code of the error if it's a fail.CanonicalCode or codes.Code, or codes.Unknown otherwise.
+func Code(err *fail.Error) codes.Code {
+	codeRulesMu.RLock()
+	rules := codeRules
+	codeRulesMu.RUnlock()
+
+	for _, r := range rules {
+		if r.matcher(err) {
+			return r.code
+		}
+	}
+
+	switch code := err.Code.(type) {
+	case fail.CanonicalCode:
+		return codes.Code(code)
+	case codes.Code:
+		return code
+	}
+	return codes.Unknown
+}
+
+// Status converts the error into a gRPC status.
+// The violations of an error built by fail.Validation are attached as errdetails.BadRequest,
+// and the duration of fail.WithRetryAfter is attached as errdetails.RetryInfo.
+// It returns the status as is if the error is already a gRPC status, and nil if err is nil.
+func Status(err error) *status.Status {
+	if err == nil {
+		return nil
+	}
+	if s, ok := status.FromError(err); ok {
+		return s
+	}
+
+	failErr := fail.Unwrap(err)
+	if failErr == nil {
+		failErr = &fail.Error{Err: err}
+	}
+	st := status.New(Code(failErr), failErr.Error())
+
+	var details []protoadapt.MessageV1
+	if violations := fail.FieldViolations(failErr); len(violations) > 0 {
+		br := &errdetails.BadRequest{}
+		for _, v := range violations {
+			br.FieldViolations = append(br.FieldViolations, &errdetails.BadRequest_FieldViolation{
+				Field:       v.Field,
+				Description: v.Message,
+			})
+		}
+		details = append(details, br)
+	}
+	if failErr.RetryAfter > 0 {
+		details = append(details, &errdetails.RetryInfo{RetryDelay: durationpb.New(failErr.RetryAfter)})
+	}
+
+	if len(details) > 0 {
+		if withDetails, err := st.WithDetails(details...); err == nil {
+			st = withDetails
+		}
+	}
+	return st
+}
+
+// UnaryServerInterceptor returns an interceptor that converts errors returned from handlers into gRPC statuses
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		resp, err := handler(ctx, req)
+		if err != nil {
+			return resp, Status(err).Err()
+		}
+		return resp, nil
+	}
+}
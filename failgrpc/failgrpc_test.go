@@ -0,0 +1,98 @@
+package failgrpc
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/srvc/fail/v4"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestCode(t *testing.T) {
+	assert.Equal(t, codes.NotFound, Code(&fail.Error{Code: fail.CodeNotFound}))
+	assert.Equal(t, codes.Unauthenticated, Code(&fail.Error{Code: fail.CodeUnauthenticated}))
+	assert.Equal(t, codes.Aborted, Code(&fail.Error{Code: codes.Aborted}))
+	assert.Equal(t, codes.Unknown, Code(&fail.Error{Code: 404}))
+	assert.Equal(t, codes.Unknown, Code(&fail.Error{}))
+}
+
+func TestRegisterCode(t *testing.T) {
+	defer func() { codeRules = nil }()
+	RegisterCode(fail.MustParseMatcher("tag:db && code:NotFound"), codes.Unavailable)
+	RegisterCode(fail.Match.Tag("db"), codes.Internal)
+
+	assert.Equal(t, codes.Unavailable, Code(&fail.Error{Code: fail.CodeNotFound, Tags: []string{"db"}}))
+	assert.Equal(t, codes.Internal, Code(&fail.Error{Tags: []string{"db"}}))
+	assert.Equal(t, codes.NotFound, Code(&fail.Error{Code: fail.CodeNotFound}))
+}
+
+func TestStatus(t *testing.T) {
+	t.Run("nil", func(t *testing.T) {
+		assert.Nil(t, Status(nil))
+	})
+
+	t.Run("plain", func(t *testing.T) {
+		st := Status(errors.New("origin"))
+		assert.Equal(t, codes.Unknown, st.Code())
+		assert.Equal(t, "origin", st.Message())
+		assert.Empty(t, st.Details())
+	})
+
+	t.Run("code", func(t *testing.T) {
+		st := Status(fail.Wrap(fail.New("origin"), fail.WithMessage("message"), fail.WithCode(fail.CodeNotFound)))
+		assert.Equal(t, codes.NotFound, st.Code())
+		assert.Equal(t, "message: origin", st.Message())
+	})
+
+	t.Run("status", func(t *testing.T) {
+		err := status.Error(codes.PermissionDenied, "denied")
+		assert.Equal(t, codes.PermissionDenied, Status(err).Code())
+	})
+
+	t.Run("validation", func(t *testing.T) {
+		v := fail.NewValidation()
+		v.Add("name", "required", "must not be empty")
+		v.In("address").Add("zip", "len", "must be 7 digits")
+
+		st := Status(v.Err())
+		assert.Equal(t, codes.InvalidArgument, st.Code())
+		if assert.Len(t, st.Details(), 1) {
+			br := st.Details()[0].(*errdetails.BadRequest)
+			assert.Len(t, br.FieldViolations, 2)
+			assert.Equal(t, "name", br.FieldViolations[0].Field)
+			assert.Equal(t, "must not be empty", br.FieldViolations[0].Description)
+			assert.Equal(t, "address.zip", br.FieldViolations[1].Field)
+		}
+	})
+
+	t.Run("retry after", func(t *testing.T) {
+		st := Status(fail.Wrap(fail.New("origin"), fail.WithCode(fail.CodeUnavailable), fail.WithRetryAfter(3*time.Second)))
+		assert.Equal(t, codes.Unavailable, st.Code())
+		if assert.Len(t, st.Details(), 1) {
+			ri := st.Details()[0].(*errdetails.RetryInfo)
+			assert.Equal(t, 3*time.Second, ri.RetryDelay.AsDuration())
+		}
+	})
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	interceptor := UnaryServerInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: "/test.Service/Method"}
+
+	_, err := interceptor(context.Background(), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, fail.Wrap(fail.New("origin"), fail.WithCode(fail.CodeNotFound))
+	})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	resp, err := interceptor(context.Background(), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "ok", nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "ok", resp)
+}
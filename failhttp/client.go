@@ -0,0 +1,210 @@
+package failhttp
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/srvc/fail/v4"
+)
+
+// TagRemote is the tag annotated to errors decoded from responses of upstream services
+const TagRemote = "remote"
+
+// Keys of the params of errors decoded from responses
+const (
+	ParamMethod = "method"
+	ParamURL    = "url"
+	ParamStatus = "status"
+)
+
+// maxBodySize is the maximum size of the body of an error response to read
+const maxBodySize = 1 << 20
+
+// canonicalCodes maps HTTP status codes to canonical codes
+var canonicalCodes = map[int]fail.CanonicalCode{
+	http.StatusBadRequest:          fail.CodeInvalidArgument,
+	http.StatusUnauthorized:        fail.CodeUnauthenticated,
+	http.StatusForbidden:           fail.CodePermissionDenied,
+	http.StatusNotFound:            fail.CodeNotFound,
+	http.StatusConflict:            fail.CodeAlreadyExists,
+	http.StatusGone:                fail.CodeNotFound,
+	http.StatusPreconditionFailed:  fail.CodeFailedPrecondition,
+	http.StatusUnprocessableEntity: fail.CodeInvalidArgument,
+	http.StatusTooManyRequests:     fail.CodeResourceExhausted,
+	499:                            fail.CodeCanceled,
+	http.StatusInternalServerError: fail.CodeInternal,
+	http.StatusNotImplemented:      fail.CodeUnimplemented,
+	http.StatusBadGateway:          fail.CodeUnavailable,
+	http.StatusServiceUnavailable:  fail.CodeUnavailable,
+	http.StatusGatewayTimeout:      fail.CodeDeadlineExceeded,
+}
+
+// CanonicalCode returns the canonical code of the HTTP status code, or fail.CodeUnknown if it's not mapped
+func CanonicalCode(status int) fail.CanonicalCode {
+	if code, ok := canonicalCodes[status]; ok {
+		return code
+	}
+	return fail.CodeUnknown
+}
+
+// Transport is an http.RoundTripper that converts error responses, 4xx and 5xx, into errors by DecodeResponse.
+// Other responses are returned as is, so that http.Client follows redirects.
+// Note that http.Client wraps the errors with *url.Error, so use errors.As to extract them.
+type Transport struct {
+	// Base is the RoundTripper to send requests, or http.DefaultTransport if nil
+	Base http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	resp, err := base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	if err := decodeResponse(resp, 2); err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+	return resp, nil
+}
+
+// DecodeResponse converts an error response, 4xx or 5xx, into an error, or returns nil for other responses.
+//
+// The body is decoded as a Problem for application/problem+json, as a serialized *fail.Error for JSON
+// that has the "error" field, or as the message otherwise.
+// The error is annotated with the code mapped from the status by CanonicalCode, the method, the URL and the status in the params,
+// where the URL has neither the userinfo nor the query since they may carry credentials,
+// the duration of the Retry-After header, and TagRemote.
+// Frames of a serialized *fail.Error are marked as remote with the host of the URL as the origin.
+func DecodeResponse(resp *http.Response) error {
+	return decodeResponse(resp, 2)
+}
+
+// decodeResponse is the implementation of DecodeResponse.
+// The skip is the number of frames to remove from the top of the local stack trace.
+func decodeResponse(resp *http.Response, skip int) error {
+	if resp.StatusCode < 400 {
+		return nil
+	}
+
+	body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, maxBodySize))
+	resp.Body = ioutil.NopCloser(io.MultiReader(bytes.NewReader(body), resp.Body))
+
+	annotators := []fail.Annotator{
+		fail.WithCode(CanonicalCode(resp.StatusCode)),
+		fail.WithParam(ParamStatus, resp.StatusCode),
+		fail.WithTags(TagRemote),
+	}
+	var origin fail.Origin
+	if req := resp.Request; req != nil {
+		u := *req.URL
+		u.User = nil
+		u.RawQuery = ""
+		u.ForceQuery = false
+		u.Fragment = ""
+		origin.Host = u.Host
+		annotators = append(annotators, fail.WithParams(fail.H{ParamMethod: req.Method, ParamURL: u.String()}))
+	}
+	if d, ok := retryAfter(resp.Header.Get("Retry-After")); ok {
+		annotators = append(annotators, fail.WithRetryAfter(d))
+	}
+
+	err := fail.Wrap(decodeBody(resp, body, origin), annotators...)
+	if failErr, ok := err.(*fail.Error); ok {
+		failErr.StackTrace = trimLocalFrames(failErr.StackTrace, skip)
+	}
+	return err
+}
+
+// decodeBody decodes the body of an error response into an error
+func decodeBody(resp *http.Response, body []byte, origin fail.Origin) error {
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+
+	switch {
+	case mediaType == ContentTypeProblem:
+		var p Problem
+		if json.Unmarshal(body, &p) == nil {
+			return p.err()
+		}
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		var fields struct {
+			Error *string `json:"error"`
+		}
+		var failErr fail.Error
+		if json.Unmarshal(body, &fields) == nil && fields.Error != nil && json.Unmarshal(body, &failErr) == nil {
+			for i := range failErr.StackTrace {
+				if !failErr.StackTrace[i].Remote {
+					failErr.StackTrace[i].Remote = true
+					failErr.StackTrace[i].Origin = origin
+				}
+			}
+			return &failErr
+		}
+	}
+
+	msg := strings.TrimSpace(string(body))
+	if msg == "" {
+		msg = http.StatusText(resp.StatusCode)
+	}
+	return &fail.DecodedError{Message: msg}
+}
+
+// err returns the root error of the problem.
+// It's *fail.ValidationError if the problem has "invalid-params".
+func (p *Problem) err() error {
+	if len(p.InvalidParams) > 0 {
+		verr := &fail.ValidationError{}
+		for _, ip := range p.InvalidParams {
+			verr.Violations = append(verr.Violations, fail.FieldViolation{Field: ip.Name, Message: ip.Reason})
+		}
+		return verr
+	}
+
+	msg := p.Detail
+	if msg == "" {
+		msg = p.Title
+	}
+	return &fail.DecodedError{Message: msg}
+}
+
+// retryAfter parses the value of the Retry-After header in seconds or an HTTP date
+func retryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if sec, err := strconv.Atoi(value); err == nil && sec >= 0 {
+		return time.Duration(sec) * time.Second, true
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d, true
+		}
+	}
+	return 0, false
+}
+
+// trimLocalFrames removes n frames from the top of the local frames
+func trimLocalFrames(st fail.StackTrace, n int) fail.StackTrace {
+	for i, f := range st {
+		if f.Remote {
+			continue
+		}
+		if i+n > len(st) {
+			n = len(st) - i
+		}
+		return append(st[:i:i], st[i+n:]...)
+	}
+	return st
+}
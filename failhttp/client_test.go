@@ -0,0 +1,204 @@
+package failhttp
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/srvc/fail/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func newUpstream() *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ok", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	})
+	mux.HandleFunc("/problem", func(w http.ResponseWriter, r *http.Request) {
+		WriteProblem(w, fail.Wrap(fail.New("user not found"), fail.WithCode(fail.CodeNotFound)))
+	})
+	mux.HandleFunc("/validation", func(w http.ResponseWriter, r *http.Request) {
+		v := fail.NewValidation()
+		v.Add("name", "required", "must not be empty")
+		WriteProblem(w, v.Err())
+	})
+	mux.HandleFunc("/fail", func(w http.ResponseWriter, r *http.Request) {
+		err := fail.Wrap(fail.New("connection refused"), fail.WithMessage("failed to query"), fail.WithTags("db"))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		json.NewEncoder(w).Encode(err)
+	})
+	mux.HandleFunc("/json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadGateway)
+		w.Write([]byte(`{"message": "upstream is down"}`))
+	})
+	mux.HandleFunc("/text", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "30")
+		http.Error(w, "slow down", http.StatusTooManyRequests)
+	})
+	mux.HandleFunc("/empty", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	})
+	mux.HandleFunc("/redirect", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/ok", http.StatusFound)
+	})
+	mux.HandleFunc("/not-modified", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotModified)
+	})
+	return httptest.NewServer(mux)
+}
+
+func TestDecodeResponse(t *testing.T) {
+	srv := newUpstream()
+	defer srv.Close()
+
+	get := func(t *testing.T, path string) error {
+		resp, err := http.Get(srv.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		return DecodeResponse(resp)
+	}
+
+	t.Run("ok", func(t *testing.T) {
+		assert.NoError(t, get(t, "/ok"))
+	})
+
+	t.Run("problem", func(t *testing.T) {
+		err := fail.Unwrap(get(t, "/problem"))
+		assert.Equal(t, &fail.DecodedError{Message: "user not found"}, err.Err)
+		assert.Equal(t, fail.CodeNotFound, err.Code)
+		assert.Equal(t, []string{"remote"}, err.Tags)
+		assert.Equal(t, fail.H{"method": "GET", "url": srv.URL + "/problem", "status": 404}, err.Params)
+		assert.Equal(t, "TestDecodeResponse.func1", err.StackTrace[0].Func)
+		assert.False(t, err.CreatedAt.IsZero())
+	})
+
+	t.Run("validation problem", func(t *testing.T) {
+		err := get(t, "/validation")
+		assert.Equal(t, []fail.FieldViolation{{Field: "name", Message: "must not be empty"}}, fail.FieldViolations(err))
+		assert.Equal(t, fail.CodeInvalidArgument, fail.Unwrap(err).Code)
+	})
+
+	t.Run("fail", func(t *testing.T) {
+		err := fail.Unwrap(get(t, "/fail"))
+		assert.Equal(t, "failed to query: connection refused", err.Error())
+		assert.Equal(t, fail.CodeUnavailable, err.Code)
+		assert.Equal(t, []string{"db", "remote"}, err.Tags)
+		assert.True(t, fail.IsRetryable(err))
+
+		remote := err.StackTrace.Remote()
+		if assert.NotEmpty(t, remote) {
+			assert.Equal(t, "newUpstream.func4", remote[0].Func)
+			assert.Equal(t, srv.Listener.Addr().String(), remote[0].Origin.Host)
+		}
+		local := err.StackTrace.Local()
+		if assert.NotEmpty(t, local) {
+			assert.Equal(t, "TestDecodeResponse.func1", local[0].Func)
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		err := fail.Unwrap(get(t, "/json"))
+		assert.Equal(t, `{"message": "upstream is down"}`, err.Err.Error())
+		assert.Equal(t, fail.CodeUnavailable, err.Code)
+	})
+
+	t.Run("text", func(t *testing.T) {
+		err := fail.Unwrap(get(t, "/text"))
+		assert.Equal(t, "slow down", err.Error())
+		assert.Equal(t, fail.CodeResourceExhausted, err.Code)
+		assert.Equal(t, 30*time.Second, err.RetryAfter)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		err := fail.Unwrap(get(t, "/empty"))
+		assert.Equal(t, "Forbidden", err.Error())
+		assert.Equal(t, fail.CodePermissionDenied, err.Code)
+	})
+
+	t.Run("not modified", func(t *testing.T) {
+		assert.NoError(t, get(t, "/not-modified"))
+	})
+
+	t.Run("url without credentials", func(t *testing.T) {
+		resp, err := http.Get(srv.URL + "/problem?token=secret#frag")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		resp.Request.URL.User = url.UserPassword("user", "pass")
+
+		err = DecodeResponse(resp)
+		assert.Equal(t, srv.URL+"/problem", fail.Unwrap(err).Params[ParamURL])
+	})
+
+	t.Run("body is still readable", func(t *testing.T) {
+		resp, err := http.Get(srv.URL + "/text")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+
+		assert.Error(t, DecodeResponse(resp))
+		body, _ := ioutil.ReadAll(resp.Body)
+		assert.Equal(t, "slow down\n", string(body))
+	})
+}
+
+func TestTransport(t *testing.T) {
+	srv := newUpstream()
+	defer srv.Close()
+
+	client := &http.Client{Transport: &Transport{}}
+
+	t.Run("ok", func(t *testing.T) {
+		resp, err := client.Get(srv.URL + "/ok")
+		if assert.NoError(t, err) {
+			defer resp.Body.Close()
+			body, _ := ioutil.ReadAll(resp.Body)
+			assert.Equal(t, "ok", string(body))
+		}
+	})
+
+	t.Run("redirect", func(t *testing.T) {
+		resp, err := client.Get(srv.URL + "/redirect")
+		if assert.NoError(t, err) {
+			defer resp.Body.Close()
+			body, _ := ioutil.ReadAll(resp.Body)
+			assert.Equal(t, "ok", string(body))
+		}
+	})
+
+	t.Run("not modified", func(t *testing.T) {
+		resp, err := client.Get(srv.URL + "/not-modified")
+		if assert.NoError(t, err) {
+			defer resp.Body.Close()
+			assert.Equal(t, http.StatusNotModified, resp.StatusCode)
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		resp, err := client.Get(srv.URL + "/problem")
+		assert.Nil(t, resp)
+
+		var failErr *fail.Error
+		if assert.True(t, errors.As(err, &failErr)) {
+			assert.Equal(t, fail.CodeNotFound, failErr.Code)
+			assert.Equal(t, []string{"remote"}, failErr.Tags)
+		}
+	})
+}
+
+func TestCanonicalCode(t *testing.T) {
+	assert.Equal(t, fail.CodeNotFound, CanonicalCode(404))
+	assert.Equal(t, fail.CodeDeadlineExceeded, CanonicalCode(504))
+	assert.Equal(t, fail.CodeUnknown, CanonicalCode(418))
+}
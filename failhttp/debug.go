@@ -0,0 +1,283 @@
+package failhttp
+
+import (
+	"bytes"
+	"html/template"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/srvc/fail/v4"
+)
+
+// ProductionEnvs are environment variables that tell the application runs in production
+// when they are "production" or "prod"
+var ProductionEnvs = []string{"APP_ENV", "GO_ENV", "ENV"}
+
+// DebugOption configures DebugHandler
+type DebugOption func(*debugConfig)
+
+type debugConfig struct {
+	enabled       bool
+	inAppPrefixes []string
+	source        *fail.SourceLoader
+}
+
+// Enabled enables the debug page.
+// The page is never shown in production even if it's enabled.
+func Enabled(enabled bool) DebugOption {
+	return func(c *debugConfig) {
+		c.enabled = enabled
+	}
+}
+
+// WithInAppPrefixes sets prefixes of files or functions that belong to the application, as fail.Frame.InApp.
+// Other frames are shown as library frames.
+func WithInAppPrefixes(prefixes ...string) DebugOption {
+	return func(c *debugConfig) {
+		c.inAppPrefixes = append(c.inAppPrefixes, prefixes...)
+	}
+}
+
+// WithSourceLoader sets the SourceLoader for source code around frames.
+// It reads 5 lines around each frame from the local file system by default.
+func WithSourceLoader(l *fail.SourceLoader) DebugOption {
+	return func(c *debugConfig) {
+		c.source = l
+	}
+}
+
+// DebugHandler returns an http.Handler that calls the handler and renders an error page
+// if it returns an error, with the messages, the metadata and the stack trace of the error.
+//
+// The page is for local development, and is disabled unless Enabled(true) is given.
+// It's also disabled in production, that is, any of ProductionEnvs is "production" or "prod".
+// Only the status text is written if the page is disabled.
+func DebugHandler(h HandlerFunc, opts ...DebugOption) http.Handler {
+	cfg := &debugConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	if cfg.source == nil {
+		cfg.source = fail.NewSourceLoader(5, nil)
+	}
+	enabled := cfg.enabled && !isProduction()
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		err := h(w, r)
+		if err == nil {
+			return
+		}
+
+		failErr := fail.Unwrap(err)
+		if failErr == nil {
+			failErr = &fail.Error{Err: err}
+		}
+		status := StatusCode(failErr)
+
+		if !enabled {
+			http.Error(w, http.StatusText(status), status)
+			return
+		}
+
+		// renders the page into a buffer first, so that a broken page falls back to a plain-text error
+		var buf bytes.Buffer
+		if err := debugTemplate.Execute(&buf, newDebugPage(cfg, r, failErr, status)); err != nil {
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		w.WriteHeader(status)
+		buf.WriteTo(w)
+	})
+}
+
+// isProduction reports whether the application runs in production
+func isProduction() bool {
+	for _, env := range ProductionEnvs {
+		switch strings.ToLower(os.Getenv(env)) {
+		case "production", "prod":
+			return true
+		}
+	}
+	return false
+}
+
+type debugPage struct {
+	Status     int
+	StatusText string
+	Method     string
+	URL        string
+	Messages   []string
+	RootType   string
+	Kind       string
+	Code       interface{}
+	Ignorable  bool
+	Severity   fail.Severity
+	Tags       []string
+	Params     []debugParam
+	Labels     []debugParam
+	Goroutine  int64
+	CreatedAt  time.Time
+	WrappedAt  time.Time
+	Frames     []debugFrame
+}
+
+type debugParam struct {
+	Key   string
+	Value interface{}
+}
+
+type debugFrame struct {
+	fail.Frame
+	Separator string
+	InApp     bool
+	Source    []fail.SourceLine
+}
+
+func newDebugPage(cfg *debugConfig, r *http.Request, err *fail.Error, status int) *debugPage {
+	p := &debugPage{
+		Status:     status,
+		StatusText: http.StatusText(status),
+		Method:     r.Method,
+		URL:        r.URL.String(),
+		Messages:   append(append([]string{}, err.Messages...), err.Err.Error()),
+		RootType:   fail.RootTypeName(err.Err),
+		Code:       err.Code,
+		Ignorable:  err.Ignorable,
+		Severity:   err.EffectiveSeverity(),
+		Tags:       err.Tags,
+		Goroutine:  err.GoroutineID,
+		CreatedAt:  err.CreatedAt,
+		WrappedAt:  err.WrappedAt,
+	}
+	if err.Kind != nil {
+		p.Kind = err.Kind.ID()
+	}
+
+	for _, param := range err.Params.Flatten() {
+		p.Params = append(p.Params, debugParam{Key: param.Path, Value: param.Value})
+	}
+
+	keys := make([]string, 0, len(err.Labels))
+	for k := range err.Labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		p.Labels = append(p.Labels, debugParam{Key: k, Value: err.Labels[k]})
+	}
+
+	for i, f := range err.StackTrace {
+		df := debugFrame{Frame: f, Separator: err.StackTrace.Separator(i), InApp: f.InApp(cfg.inAppPrefixes...)}
+		if src, ok := cfg.source.Load(f); ok && !f.Remote {
+			df.Source = src.Lines()
+		}
+		p.Frames = append(p.Frames, df)
+	}
+
+	return p
+}
+
+var debugTemplate = template.Must(template.New("debug").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Status}} {{.StatusText}}: {{index .Messages 0}}</title>
+<style>
+body { font-family: -apple-system, BlinkMacSystemFont, sans-serif; margin: 0; color: #222; }
+header { background: #b71c1c; color: #fff; padding: 1.5em 2em; }
+header h1 { margin: 0 0 .3em; font-size: 1.4em; }
+header p { margin: 0; opacity: .8; }
+main { padding: 1em 2em; }
+ol.messages { padding-left: 1.5em; }
+ol.messages li:last-child { font-weight: bold; }
+table { border-collapse: collapse; margin-bottom: 1em; }
+th, td { border: 1px solid #ddd; padding: .3em .8em; text-align: left; vertical-align: top; }
+th { background: #f5f5f5; }
+code, pre { font-family: SFMono-Regular, Menlo, monospace; font-size: .9em; }
+details.frame { border-left: 4px solid #b71c1c; margin: .5em 0; padding: .3em .8em; background: #fafafa; }
+details.frame.library { border-left-color: #bbb; color: #777; }
+details.frame summary { cursor: pointer; }
+p.separator { color: #777; font-family: monospace; margin: 1em 0 .5em; }
+pre.source { margin: .5em 0 0; padding: .5em; background: #fff; overflow-x: auto; }
+pre.source .current { display: block; background: #ffebee; }
+</style>
+</head>
+<body>
+<header>
+<h1>{{index .Messages 0}}</h1>
+<p>{{.Status}} {{.StatusText}} &middot; {{.Method}} {{.URL}}</p>
+</header>
+<main>
+<h2>Messages</h2>
+<ol class="messages">
+{{- range .Messages}}
+<li>{{.}}</li>
+{{- end}}
+</ol>
+
+<h2>Error</h2>
+<table>
+<tr><th>type</th><td><code>{{.RootType}}</code></td></tr>
+{{- if .Kind}}
+<tr><th>kind</th><td><code>{{.Kind}}</code></td></tr>
+{{- end}}
+{{- if .Code}}
+<tr><th>code</th><td><code>{{.Code}}</code></td></tr>
+{{- end}}
+<tr><th>severity</th><td>{{.Severity}}</td></tr>
+<tr><th>ignorable</th><td>{{.Ignorable}}</td></tr>
+{{- if .Tags}}
+<tr><th>tags</th><td>{{range $i, $t := .Tags}}{{if $i}}, {{end}}<code>{{$t}}</code>{{end}}</td></tr>
+{{- end}}
+{{- if .Goroutine}}
+<tr><th>goroutine</th><td><code>{{.Goroutine}}</code></td></tr>
+{{- end}}
+{{- if not .CreatedAt.IsZero}}
+<tr><th>created at</th><td>{{.CreatedAt.Format "2006-01-02T15:04:05.000Z07:00"}}</td></tr>
+{{- end}}
+{{- if not .WrappedAt.IsZero}}
+<tr><th>wrapped at</th><td>{{.WrappedAt.Format "2006-01-02T15:04:05.000Z07:00"}}</td></tr>
+{{- end}}
+</table>
+
+{{- if .Params}}
+<h2>Params</h2>
+<table>
+{{- range .Params}}
+<tr><th>{{.Key}}</th><td><code>{{printf "%v" .Value}}</code></td></tr>
+{{- end}}
+</table>
+{{- end}}
+
+{{- if .Labels}}
+<h2>Labels</h2>
+<table>
+{{- range .Labels}}
+<tr><th>{{.Key}}</th><td><code>{{.Value}}</code></td></tr>
+{{- end}}
+</table>
+{{- end}}
+
+<h2>Stack trace</h2>
+{{- range .Frames}}
+{{- if .Separator}}
+<p class="separator">{{.Separator}}</p>
+{{- end}}
+<details class="frame{{if not .InApp}} library{{end}}"{{if and .InApp .Source}} open{{end}}>
+<summary><code>{{.Func}}</code> at <code>{{.File}}:{{.Line}}</code></summary>
+{{- if .Source}}
+<pre class="source">{{range .Source}}<span{{if .Current}} class="current"{{end}}>{{printf "%5d" .Number}} | {{.Text}}
+</span>{{end}}</pre>
+{{- end}}
+</details>
+{{- end}}
+</main>
+</body>
+</html>
+`))
@@ -0,0 +1,153 @@
+package failhttp
+
+import (
+	"errors"
+	"html/template"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/srvc/fail/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+const testSource = `package app
+
+func FindUser(id int) (*User, error) {
+	return nil, fail.New("user not found")
+}`
+
+func testSourceLoader() *fail.SourceLoader {
+	return fail.NewSourceLoader(1, func(name string) (io.ReadCloser, error) {
+		if name != "user.go" {
+			return nil, os.ErrNotExist
+		}
+		return ioutil.NopCloser(strings.NewReader(testSource)), nil
+	}).WithModules("github.com/example/app")
+}
+
+var testError = &fail.Error{
+	Err:      errors.New("<script>alert(1)</script>"),
+	Messages: []string{"failed to find user"},
+	Code:     404,
+	Tags:     []string{"user"},
+	Params:   fail.H{"user_id": 1},
+	Labels:   map[string]string{"handler": "users"},
+	StackTrace: fail.StackTrace{
+		{Func: "FindUser", File: "github.com/example/app/user.go", Line: 4},
+		{Func: "ServeHTTP", File: "net/http/server.go", Line: 2220},
+	},
+	Kind:        fail.Define("not_found"),
+	GoroutineID: 18,
+	CreatedAt:   time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+}
+
+func serve(h http.Handler) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/users/1", nil))
+	return w
+}
+
+func TestDebugHandler(t *testing.T) {
+	handler := func(err error) HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) error {
+			if err != nil {
+				return err
+			}
+			_, err := w.Write([]byte("ok"))
+			return err
+		}
+	}
+
+	t.Run("enabled", func(t *testing.T) {
+		w := serve(DebugHandler(handler(testError), Enabled(true), WithInAppPrefixes("github.com/example/app/"), WithSourceLoader(testSourceLoader())))
+
+		assert.Equal(t, 404, w.Code)
+		assert.Equal(t, "text/html; charset=utf-8", w.Header().Get("Content-Type"))
+
+		body := w.Body.String()
+		for _, s := range []string{
+			"<li>failed to find user</li>",
+			"<li>&lt;script&gt;alert(1)&lt;/script&gt;</li>",
+			"<code>*errors.errorString</code>",
+			"<code>not_found</code>",
+			"<code>404</code>",
+			"<tr><th>severity</th><td>error</td></tr>",
+			"<code>user</code>",
+			"<tr><th>user_id</th><td><code>1</code></td></tr>",
+			"<tr><th>handler</th><td><code>users</code></td></tr>",
+			"<tr><th>goroutine</th><td><code>18</code></td></tr>",
+			"<tr><th>created at</th><td>2020-01-01T00:00:00.000Z</td></tr>",
+			`<details class="frame" open>`,
+			`<span class="current">    4 | 	return nil, fail.New(&#34;user not found&#34;)`,
+			`<details class="frame library">`,
+			"<code>net/http/server.go:2220</code>",
+		} {
+			assert.Contains(t, body, s)
+		}
+		assert.NotContains(t, body, "<script>")
+	})
+
+	t.Run("remote frames", func(t *testing.T) {
+		err := &fail.Error{
+			Err: errors.New("user not found"),
+			StackTrace: fail.StackTrace{
+				{Func: "FindUser", File: "github.com/example/app/user.go", Line: 4, Remote: true, Origin: fail.Origin{Service: "users"}},
+				{Func: "GetUser", File: "github.com/example/app/client.go", Line: 10},
+			},
+		}
+		w := serve(DebugHandler(handler(err), Enabled(true), WithSourceLoader(testSourceLoader())))
+
+		body := w.Body.String()
+		assert.Contains(t, body, `<p class="separator">--- remote (users) ---</p>`)
+		assert.Contains(t, body, `<p class="separator">--- local ---</p>`)
+		assert.NotContains(t, body, `class="current"`)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		w := serve(DebugHandler(handler(testError)))
+
+		assert.Equal(t, 404, w.Code)
+		assert.Equal(t, "Not Found\n", w.Body.String())
+	})
+
+	t.Run("production", func(t *testing.T) {
+		defer os.Setenv("APP_ENV", os.Getenv("APP_ENV"))
+		os.Setenv("APP_ENV", "production")
+
+		w := serve(DebugHandler(handler(testError), Enabled(true)))
+
+		assert.Equal(t, 404, w.Code)
+		assert.Equal(t, "Not Found\n", w.Body.String())
+	})
+
+	t.Run("not fail error", func(t *testing.T) {
+		w := serve(DebugHandler(handler(errors.New("origin")), Enabled(true), WithSourceLoader(testSourceLoader())))
+
+		assert.Equal(t, 500, w.Code)
+		assert.Contains(t, w.Body.String(), "<li>origin</li>")
+	})
+
+	t.Run("template error", func(t *testing.T) {
+		defer func(tmpl *template.Template) { debugTemplate = tmpl }(debugTemplate)
+		debugTemplate = template.Must(template.New("debug").Parse(`<p>{{index .Messages 10}}</p>`))
+
+		w := serve(DebugHandler(handler(testError), Enabled(true), WithSourceLoader(testSourceLoader())))
+
+		assert.Equal(t, 500, w.Code)
+		assert.Equal(t, "text/plain; charset=utf-8", w.Header().Get("Content-Type"))
+		assert.Equal(t, "Internal Server Error\n", w.Body.String())
+	})
+
+	t.Run("no error", func(t *testing.T) {
+		w := serve(DebugHandler(handler(nil), Enabled(true)))
+
+		assert.Equal(t, 200, w.Code)
+		assert.Equal(t, "ok", w.Body.String())
+	})
+}
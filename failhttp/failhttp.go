@@ -0,0 +1,80 @@
+// Package failhttp provides HTTP integrations of fail.
+package failhttp
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/srvc/fail/v4"
+)
+
+// HandlerFunc is an HTTP handler that returns an error
+type HandlerFunc func(w http.ResponseWriter, r *http.Request) error
+
+// httpStatuses maps canonical codes to HTTP status codes
+var httpStatuses = map[fail.CanonicalCode]int{
+	fail.CodeCanceled:           499,
+	fail.CodeUnknown:            http.StatusInternalServerError,
+	fail.CodeInvalidArgument:    http.StatusBadRequest,
+	fail.CodeDeadlineExceeded:   http.StatusGatewayTimeout,
+	fail.CodeNotFound:           http.StatusNotFound,
+	fail.CodeAlreadyExists:      http.StatusConflict,
+	fail.CodePermissionDenied:   http.StatusForbidden,
+	fail.CodeResourceExhausted:  http.StatusTooManyRequests,
+	fail.CodeFailedPrecondition: http.StatusBadRequest,
+	fail.CodeAborted:            http.StatusConflict,
+	fail.CodeOutOfRange:         http.StatusBadRequest,
+	fail.CodeUnimplemented:      http.StatusNotImplemented,
+	fail.CodeInternal:           http.StatusInternalServerError,
+	fail.CodeUnavailable:        http.StatusServiceUnavailable,
+	fail.CodeDataLoss:           http.StatusInternalServerError,
+	fail.CodeUnauthenticated:    http.StatusUnauthorized,
+}
+
+type statusRule struct {
+	matcher fail.Matcher
+	status  int
+}
+
+var (
+	statusRulesMu sync.RWMutex
+	statusRules   []statusRule
+)
+
+// RegisterStatus maps errors that match the matcher to the HTTP status code.
+// Rules are consulted by StatusCode in the order they were registered, before the code of the error.
+//
+//	failhttp.RegisterStatus(fail.MustParseMatcher("tag:db && retryable"), http.StatusServiceUnavailable)
+func RegisterStatus(m fail.Matcher, status int) {
+	statusRulesMu.Lock()
+	defer statusRulesMu.Unlock()
+	statusRules = append(statusRules, statusRule{matcher: m, status: status})
+}
+
+// StatusCode returns the HTTP status code of the error.
+// It's the status of the first rule registered by RegisterStatus that matches the error,
+// the code of the error if it's an HTTP error status code,
+// the one mapped from a fail.CanonicalCode, or 500 otherwise.
+func StatusCode(err *fail.Error) int {
+	statusRulesMu.RLock()
+	rules := statusRules
+	statusRulesMu.RUnlock()
+
+	for _, r := range rules {
+		if r.matcher(err) {
+			return r.status
+		}
+	}
+
+	switch code := err.Code.(type) {
+	case int:
+		if code >= 400 && code < 600 {
+			return code
+		}
+	case fail.CanonicalCode:
+		if status, ok := httpStatuses[code]; ok {
+			return status
+		}
+	}
+	return http.StatusInternalServerError
+}
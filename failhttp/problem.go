@@ -0,0 +1,59 @@
+package failhttp
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/srvc/fail/v4"
+)
+
+// ContentTypeProblem is the media type of Problem
+const ContentTypeProblem = "application/problem+json"
+
+// Problem is a problem details object defined in RFC 7807
+type Problem struct {
+	Type          string         `json:"type"`
+	Title         string         `json:"title"`
+	Status        int            `json:"status"`
+	Detail        string         `json:"detail,omitempty"`
+	InvalidParams []InvalidParam `json:"invalid-params,omitempty"`
+}
+
+// InvalidParam is an entry of "invalid-params" of Problem
+type InvalidParam struct {
+	Name   string `json:"name"`
+	Reason string `json:"reason"`
+}
+
+// NewProblem creates a Problem of the error.
+// The detail is the error message for client errors, and is omitted for server errors
+// so that internal details are not exposed.
+// The violations of an error built by fail.Validation are converted into "invalid-params".
+func NewProblem(err error) *Problem {
+	failErr := fail.Unwrap(err)
+	if failErr == nil {
+		failErr = &fail.Error{Err: err}
+	}
+	status := StatusCode(failErr)
+
+	p := &Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(status),
+		Status: status,
+	}
+	if status < 500 {
+		p.Detail = failErr.Error()
+	}
+	for _, v := range fail.FieldViolations(failErr) {
+		p.InvalidParams = append(p.InvalidParams, InvalidParam{Name: v.Field, Reason: v.Message})
+	}
+	return p
+}
+
+// WriteProblem writes the Problem of the error as application/problem+json
+func WriteProblem(w http.ResponseWriter, err error) {
+	p := NewProblem(err)
+	w.Header().Set("Content-Type", ContentTypeProblem)
+	w.WriteHeader(p.Status)
+	json.NewEncoder(w).Encode(p)
+}
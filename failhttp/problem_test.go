@@ -0,0 +1,73 @@
+package failhttp
+
+import (
+	"errors"
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/srvc/fail/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatusCode(t *testing.T) {
+	cases := []struct {
+		code interface{}
+		want int
+	}{
+		{code: nil, want: 500},
+		{code: 404, want: 404},
+		{code: 200, want: 500},
+		{code: "not_found", want: 500},
+		{code: fail.CodeInvalidArgument, want: 400},
+		{code: fail.CodeUnauthenticated, want: 401},
+		{code: fail.CodeUnavailable, want: 503},
+		{code: fail.CanonicalCode(100), want: 500},
+	}
+
+	for _, c := range cases {
+		assert.Equal(t, c.want, StatusCode(&fail.Error{Code: c.code}), "code %v", c.code)
+	}
+}
+
+func TestRegisterStatus(t *testing.T) {
+	defer func() { statusRules = nil }()
+	RegisterStatus(fail.MustParseMatcher("tag:db && retryable"), 503)
+	RegisterStatus(fail.Match.RootIs(io.EOF), 404)
+
+	assert.Equal(t, 503, StatusCode(&fail.Error{Err: io.EOF, Tags: []string{"db"}, Retryable: true}))
+	assert.Equal(t, 404, StatusCode(&fail.Error{Err: io.EOF, Code: 500}))
+	assert.Equal(t, 500, StatusCode(&fail.Error{Err: errors.New("origin"), Tags: []string{"db"}}))
+}
+
+func TestWriteProblem(t *testing.T) {
+	t.Run("validation", func(t *testing.T) {
+		v := fail.NewValidation()
+		v.Add("name", "required", "must not be empty")
+		v.Add("age", "min", "must be at least 0")
+
+		w := httptest.NewRecorder()
+		WriteProblem(w, v.Err())
+
+		assert.Equal(t, 400, w.Code)
+		assert.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
+		assert.JSONEq(t, `{
+			"type": "about:blank",
+			"title": "Bad Request",
+			"status": 400,
+			"detail": "invalid argument: name: must not be empty; age: must be at least 0",
+			"invalid-params": [
+				{"name": "name", "reason": "must not be empty"},
+				{"name": "age", "reason": "must be at least 0"}
+			]
+		}`, w.Body.String())
+	})
+
+	t.Run("server error", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		WriteProblem(w, errors.New("connection refused"))
+
+		assert.Equal(t, 500, w.Code)
+		assert.JSONEq(t, `{"type": "about:blank", "title": "Internal Server Error", "status": 500}`, w.Body.String())
+	})
+}
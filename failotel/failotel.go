@@ -0,0 +1,160 @@
+// Package failotel records fail errors on OpenTelemetry spans.
+//
+// Importing this package also registers a context extractor,
+// so errors wrapped with fail.WrapContext carry the trace and span IDs
+// of the span active in the context.
+package failotel
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/srvc/fail/v4"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// ParamTraceID is the param key for the trace ID extracted from a context
+	ParamTraceID = "trace_id"
+	// ParamSpanID is the param key for the span ID extracted from a context
+	ParamSpanID = "span_id"
+)
+
+const (
+	eventName = "exception"
+
+	exceptionTypeKey       = attribute.Key("exception.type")
+	exceptionMessageKey    = attribute.Key("exception.message")
+	exceptionStacktraceKey = attribute.Key("exception.stacktrace")
+
+	codeKey         = attribute.Key("fail.code")
+	tagsKey         = attribute.Key("fail.tags")
+	ignorableKey    = attribute.Key("fail.ignorable")
+	severityKey     = attribute.Key("fail.severity")
+	paramsKeyPrefix = "fail.params."
+	labelsKeyPrefix = "fail.labels."
+	goroutineKey    = attribute.Key("fail.goroutine_id")
+	createdAtKey    = attribute.Key("fail.created_at")
+	wrappedAtKey    = attribute.Key("fail.wrapped_at")
+)
+
+func init() {
+	fail.RegisterContextExtractor(ExtractContext)
+}
+
+// ExtractContext returns the trace and span IDs of the span in the context.
+// It returns nil if the context has no valid span.
+func ExtractContext(ctx context.Context) fail.H {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return nil
+	}
+	return fail.H{
+		ParamTraceID: sc.TraceID().String(),
+		ParamSpanID:  sc.SpanID().String(),
+	}
+}
+
+// RecordError records an error on the span.
+// It adds an exception event built from the error and its stack trace,
+// maps the severity, code, tags, params, labels, goroutine ID and timestamps to span attributes,
+// and sets the span status to Error unless the error is ignorable.
+// It does nothing if err is nil or the span is not recording.
+func RecordError(span trace.Span, err error) {
+	if err == nil || !span.IsRecording() {
+		return
+	}
+
+	failErr := fail.Unwrap(err)
+	if failErr == nil {
+		failErr = &fail.Error{Err: err}
+	}
+
+	span.AddEvent(eventName, trace.WithAttributes(
+		exceptionTypeKey.String(fmt.Sprintf("%T", failErr.Err)),
+		exceptionMessageKey.String(failErr.Error()),
+		exceptionStacktraceKey.String(formatStackTrace(failErr.StackTrace)),
+	))
+	span.SetAttributes(attributes(failErr)...)
+
+	if !failErr.Ignorable {
+		span.SetStatus(codes.Error, failErr.Error())
+	}
+}
+
+// attributes converts metadata of the error into span attributes
+func attributes(err *fail.Error) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{
+		ignorableKey.Bool(err.Ignorable),
+		severityKey.String(err.EffectiveSeverity().String()),
+	}
+
+	if err.Code != nil {
+		attrs = append(attrs, codeKey.String(fmt.Sprint(err.Code)))
+	}
+	if len(err.Tags) > 0 {
+		attrs = append(attrs, tagsKey.StringSlice(err.Tags))
+	}
+
+	for _, param := range err.Params.Flatten() {
+		attrs = append(attrs, paramAttribute(paramsKeyPrefix+param.Path, param.Value))
+	}
+
+	keys := make([]string, 0, len(err.Labels))
+	for k := range err.Labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		attrs = append(attrs, attribute.String(labelsKeyPrefix+k, err.Labels[k]))
+	}
+
+	if err.GoroutineID != 0 {
+		attrs = append(attrs, goroutineKey.Int64(err.GoroutineID))
+	}
+	if !err.CreatedAt.IsZero() {
+		attrs = append(attrs, createdAtKey.String(err.CreatedAt.Format(time.RFC3339Nano)))
+	}
+	if !err.WrappedAt.IsZero() {
+		attrs = append(attrs, wrappedAtKey.String(err.WrappedAt.Format(time.RFC3339Nano)))
+	}
+
+	return attrs
+}
+
+// paramAttribute converts a param value into an attribute with the closest type
+func paramAttribute(key string, value interface{}) attribute.KeyValue {
+	k := attribute.Key(key)
+	switch v := value.(type) {
+	case string:
+		return k.String(v)
+	case bool:
+		return k.Bool(v)
+	case int:
+		return k.Int(v)
+	case int64:
+		return k.Int64(v)
+	case float64:
+		return k.Float64(v)
+	case []string:
+		return k.StringSlice(v)
+	case fmt.Stringer:
+		return k.String(v.String())
+	default:
+		return k.String(fmt.Sprint(v))
+	}
+}
+
+// formatStackTrace formats a stack trace in the same layout as runtime/debug.Stack
+func formatStackTrace(st fail.StackTrace) string {
+	var b strings.Builder
+	for _, f := range st {
+		fmt.Fprintf(&b, "%s\n\t%s:%d\n", f.Func, f.File, f.Line)
+	}
+	return b.String()
+}
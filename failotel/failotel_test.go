@@ -0,0 +1,134 @@
+package failotel
+
+import (
+	"context"
+	"errors"
+	"runtime/pprof"
+	"testing"
+	"time"
+
+	"github.com/srvc/fail/v4"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func newTestTracer() (*sdktrace.TracerProvider, *tracetest.InMemoryExporter) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	return provider, exporter
+}
+
+func TestRecordError(t *testing.T) {
+	t.Run("nil", func(t *testing.T) {
+		provider, exporter := newTestTracer()
+		_, span := provider.Tracer("test").Start(context.Background(), "op")
+		RecordError(span, nil)
+		span.End()
+
+		spans := exporter.GetSpans()
+		assert.Len(t, spans, 1)
+		assert.Empty(t, spans[0].Events)
+		assert.Equal(t, codes.Unset, spans[0].Status.Code)
+	})
+
+	t.Run("bare", func(t *testing.T) {
+		provider, exporter := newTestTracer()
+		_, span := provider.Tracer("test").Start(context.Background(), "op")
+		RecordError(span, errors.New("origin"))
+		span.End()
+
+		spans := exporter.GetSpans()
+		assert.Len(t, spans, 1)
+		assert.Equal(t, codes.Error, spans[0].Status.Code)
+		assert.Equal(t, "origin", spans[0].Status.Description)
+
+		assert.Len(t, spans[0].Events, 1)
+		event := spans[0].Events[0]
+		assert.Equal(t, "exception", event.Name)
+		assert.Contains(t, event.Attributes, exceptionTypeKey.String("*errors.errorString"))
+		assert.Contains(t, event.Attributes, exceptionMessageKey.String("origin"))
+		assert.Contains(t, event.Attributes, exceptionStacktraceKey.String(""))
+	})
+
+	t.Run("annotated", func(t *testing.T) {
+		provider, exporter := newTestTracer()
+		_, span := provider.Tracer("test").Start(context.Background(), "op")
+
+		defer fail.SetClock(time.Now)
+		fail.SetClock(func() time.Time { return time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC) })
+
+		err := fail.Wrap(
+			errors.New("origin"),
+			fail.WithMessage("message"),
+			fail.WithCode(500),
+			fail.WithTags("http", "db"),
+			fail.WithParams(fail.H{"user_id": 1, "name": "foo"}),
+			fail.WithLabels(pprof.WithLabels(context.Background(), pprof.Labels("handler", "users"))),
+		)
+		RecordError(span, err)
+		span.End()
+
+		spans := exporter.GetSpans()
+		assert.Len(t, spans, 1)
+		assert.Equal(t, codes.Error, spans[0].Status.Code)
+		assert.Equal(t, "message: origin", spans[0].Status.Description)
+		assert.Equal(t, []attribute.KeyValue{
+			ignorableKey.Bool(false),
+			severityKey.String("error"),
+			codeKey.String("500"),
+			tagsKey.StringSlice([]string{"http", "db"}),
+			attribute.String("fail.params.name", "foo"),
+			attribute.Int("fail.params.user_id", 1),
+			attribute.String("fail.labels.handler", "users"),
+			createdAtKey.String("2020-01-01T00:00:00Z"),
+			wrappedAtKey.String("2020-01-01T00:00:00Z"),
+		}, spans[0].Attributes)
+
+		event := spans[0].Events[0]
+		assert.Contains(t, event.Attributes, exceptionMessageKey.String("message: origin"))
+		for _, attr := range event.Attributes {
+			if attr.Key == exceptionStacktraceKey {
+				assert.Contains(t, attr.Value.AsString(), "TestRecordError.func3\n\t")
+			}
+		}
+	})
+
+	t.Run("ignorable", func(t *testing.T) {
+		provider, exporter := newTestTracer()
+		_, span := provider.Tracer("test").Start(context.Background(), "op")
+		RecordError(span, fail.Wrap(errors.New("origin"), fail.WithIgnorable()))
+		span.End()
+
+		spans := exporter.GetSpans()
+		assert.Len(t, spans, 1)
+		assert.Equal(t, codes.Unset, spans[0].Status.Code)
+		assert.Len(t, spans[0].Events, 1)
+		assert.Contains(t, spans[0].Attributes, ignorableKey.Bool(true))
+	})
+}
+
+func TestWrapContext(t *testing.T) {
+	t.Run("with span", func(t *testing.T) {
+		provider, _ := newTestTracer()
+		ctx, span := provider.Tracer("test").Start(context.Background(), "op")
+		defer span.End()
+
+		err := fail.WrapContext(ctx, errors.New("origin"))
+
+		failErr := fail.Unwrap(err)
+		assert.Equal(t, fail.H{
+			ParamTraceID: span.SpanContext().TraceID().String(),
+			ParamSpanID:  span.SpanContext().SpanID().String(),
+		}, failErr.Params)
+	})
+
+	t.Run("without span", func(t *testing.T) {
+		err := fail.WrapContext(context.Background(), errors.New("origin"))
+
+		failErr := fail.Unwrap(err)
+		assert.Empty(t, failErr.Params)
+	})
+}
@@ -0,0 +1,649 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.12
+// 	protoc        v6.33.0
+// source: fail.proto
+
+package failpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Severity is a level of an error.
+type Severity int32
+
+const (
+	Severity_SEVERITY_UNSPECIFIED Severity = 0
+	Severity_SEVERITY_DEBUG       Severity = 1
+	Severity_SEVERITY_INFO        Severity = 2
+	Severity_SEVERITY_WARNING     Severity = 3
+	Severity_SEVERITY_ERROR       Severity = 4
+	Severity_SEVERITY_FATAL       Severity = 5
+)
+
+// Enum value maps for Severity.
+var (
+	Severity_name = map[int32]string{
+		0: "SEVERITY_UNSPECIFIED",
+		1: "SEVERITY_DEBUG",
+		2: "SEVERITY_INFO",
+		3: "SEVERITY_WARNING",
+		4: "SEVERITY_ERROR",
+		5: "SEVERITY_FATAL",
+	}
+	Severity_value = map[string]int32{
+		"SEVERITY_UNSPECIFIED": 0,
+		"SEVERITY_DEBUG":       1,
+		"SEVERITY_INFO":        2,
+		"SEVERITY_WARNING":     3,
+		"SEVERITY_ERROR":       4,
+		"SEVERITY_FATAL":       5,
+	}
+)
+
+func (x Severity) Enum() *Severity {
+	p := new(Severity)
+	*p = x
+	return p
+}
+
+func (x Severity) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Severity) Descriptor() protoreflect.EnumDescriptor {
+	return file_fail_proto_enumTypes[0].Descriptor()
+}
+
+func (Severity) Type() protoreflect.EnumType {
+	return &file_fail_proto_enumTypes[0]
+}
+
+func (x Severity) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Severity.Descriptor instead.
+func (Severity) EnumDescriptor() ([]byte, []int) {
+	return file_fail_proto_rawDescGZIP(), []int{0}
+}
+
+// Error is a serialized fail.Error propagated across services.
+type Error struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// message is the message of the root error.
+	Message string `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	// type is the type name of the root error.
+	Type string `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	// messages are the annotated messages from outermost to innermost.
+	Messages []string `protobuf:"bytes,3,rep,name=messages,proto3" json:"messages,omitempty"`
+	// code is the code of the error.
+	Code *Code `protobuf:"bytes,4,opt,name=code,proto3" json:"code,omitempty"`
+	// severity is the level of the error.
+	Severity Severity `protobuf:"varint,5,opt,name=severity,proto3,enum=srvc.fail.v1.Severity" json:"severity,omitempty"`
+	// tags are the tags of the error.
+	Tags []string `protobuf:"bytes,6,rep,name=tags,proto3" json:"tags,omitempty"`
+	// params are the params of the error.
+	Params *structpb.Struct `protobuf:"bytes,7,opt,name=params,proto3" json:"params,omitempty"`
+	// frames are the stack trace from innermost to outermost.
+	Frames []*Frame `protobuf:"bytes,8,rep,name=frames,proto3" json:"frames,omitempty"`
+	// kind is the ID of the kind of the error.
+	Kind string `protobuf:"bytes,9,opt,name=kind,proto3" json:"kind,omitempty"`
+	// ignorable represents whether the error should be reported.
+	Ignorable bool `protobuf:"varint,10,opt,name=ignorable,proto3" json:"ignorable,omitempty"`
+	// retryable represents whether the operation can be retried.
+	Retryable bool `protobuf:"varint,11,opt,name=retryable,proto3" json:"retryable,omitempty"`
+	// retry_after is the duration to wait before retrying.
+	RetryAfter *durationpb.Duration `protobuf:"bytes,12,opt,name=retry_after,json=retryAfter,proto3" json:"retry_after,omitempty"`
+	// created_at is the time when the error was created.
+	CreatedAt *timestamppb.Timestamp `protobuf:"bytes,13,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	// wrapped_at is the time when the error was wrapped last.
+	WrappedAt *timestamppb.Timestamp `protobuf:"bytes,14,opt,name=wrapped_at,json=wrappedAt,proto3" json:"wrapped_at,omitempty"`
+	// goroutine_id is the ID of the goroutine where the error was created.
+	GoroutineId int64 `protobuf:"varint,15,opt,name=goroutine_id,json=goroutineId,proto3" json:"goroutine_id,omitempty"`
+	// labels are the labels of the goroutine where the error was created.
+	Labels map[string]string `protobuf:"bytes,16,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// violations are the violations of fields of a fail.ValidationError.
+	Violations    []*FieldViolation `protobuf:"bytes,17,rep,name=violations,proto3" json:"violations,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Error) Reset() {
+	*x = Error{}
+	mi := &file_fail_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Error) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Error) ProtoMessage() {}
+
+func (x *Error) ProtoReflect() protoreflect.Message {
+	mi := &file_fail_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Error.ProtoReflect.Descriptor instead.
+func (*Error) Descriptor() ([]byte, []int) {
+	return file_fail_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Error) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *Error) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Error) GetMessages() []string {
+	if x != nil {
+		return x.Messages
+	}
+	return nil
+}
+
+func (x *Error) GetCode() *Code {
+	if x != nil {
+		return x.Code
+	}
+	return nil
+}
+
+func (x *Error) GetSeverity() Severity {
+	if x != nil {
+		return x.Severity
+	}
+	return Severity_SEVERITY_UNSPECIFIED
+}
+
+func (x *Error) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *Error) GetParams() *structpb.Struct {
+	if x != nil {
+		return x.Params
+	}
+	return nil
+}
+
+func (x *Error) GetFrames() []*Frame {
+	if x != nil {
+		return x.Frames
+	}
+	return nil
+}
+
+func (x *Error) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *Error) GetIgnorable() bool {
+	if x != nil {
+		return x.Ignorable
+	}
+	return false
+}
+
+func (x *Error) GetRetryable() bool {
+	if x != nil {
+		return x.Retryable
+	}
+	return false
+}
+
+func (x *Error) GetRetryAfter() *durationpb.Duration {
+	if x != nil {
+		return x.RetryAfter
+	}
+	return nil
+}
+
+func (x *Error) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Error) GetWrappedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.WrappedAt
+	}
+	return nil
+}
+
+func (x *Error) GetGoroutineId() int64 {
+	if x != nil {
+		return x.GoroutineId
+	}
+	return 0
+}
+
+func (x *Error) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *Error) GetViolations() []*FieldViolation {
+	if x != nil {
+		return x.Violations
+	}
+	return nil
+}
+
+// Code is a typed code of an error.
+type Code struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Value:
+	//
+	//	*Code_Canonical
+	//	*Code_IntValue
+	//	*Code_StringValue
+	Value         isCode_Value `protobuf_oneof:"value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Code) Reset() {
+	*x = Code{}
+	mi := &file_fail_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Code) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Code) ProtoMessage() {}
+
+func (x *Code) ProtoReflect() protoreflect.Message {
+	mi := &file_fail_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Code.ProtoReflect.Descriptor instead.
+func (*Code) Descriptor() ([]byte, []int) {
+	return file_fail_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Code) GetValue() isCode_Value {
+	if x != nil {
+		return x.Value
+	}
+	return nil
+}
+
+func (x *Code) GetCanonical() uint32 {
+	if x != nil {
+		if x, ok := x.Value.(*Code_Canonical); ok {
+			return x.Canonical
+		}
+	}
+	return 0
+}
+
+func (x *Code) GetIntValue() int64 {
+	if x != nil {
+		if x, ok := x.Value.(*Code_IntValue); ok {
+			return x.IntValue
+		}
+	}
+	return 0
+}
+
+func (x *Code) GetStringValue() string {
+	if x != nil {
+		if x, ok := x.Value.(*Code_StringValue); ok {
+			return x.StringValue
+		}
+	}
+	return ""
+}
+
+type isCode_Value interface {
+	isCode_Value()
+}
+
+type Code_Canonical struct {
+	// canonical is a fail.CanonicalCode, which has the same values as gRPC codes.
+	Canonical uint32 `protobuf:"varint,1,opt,name=canonical,proto3,oneof"`
+}
+
+type Code_IntValue struct {
+	// int_value is an integer code, such as an HTTP status code.
+	IntValue int64 `protobuf:"varint,2,opt,name=int_value,json=intValue,proto3,oneof"`
+}
+
+type Code_StringValue struct {
+	// string_value is a string code.
+	StringValue string `protobuf:"bytes,3,opt,name=string_value,json=stringValue,proto3,oneof"`
+}
+
+func (*Code_Canonical) isCode_Value() {}
+
+func (*Code_IntValue) isCode_Value() {}
+
+func (*Code_StringValue) isCode_Value() {}
+
+// Frame is a frame of a stack trace.
+type Frame struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Func  string                 `protobuf:"bytes,1,opt,name=func,proto3" json:"func,omitempty"`
+	File  string                 `protobuf:"bytes,2,opt,name=file,proto3" json:"file,omitempty"`
+	Line  int64                  `protobuf:"varint,3,opt,name=line,proto3" json:"line,omitempty"`
+	// service is the name of the service where the frame was recorded.
+	Service string `protobuf:"bytes,4,opt,name=service,proto3" json:"service,omitempty"`
+	// host is the host where the frame was recorded.
+	Host          string `protobuf:"bytes,5,opt,name=host,proto3" json:"host,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Frame) Reset() {
+	*x = Frame{}
+	mi := &file_fail_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Frame) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Frame) ProtoMessage() {}
+
+func (x *Frame) ProtoReflect() protoreflect.Message {
+	mi := &file_fail_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Frame.ProtoReflect.Descriptor instead.
+func (*Frame) Descriptor() ([]byte, []int) {
+	return file_fail_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Frame) GetFunc() string {
+	if x != nil {
+		return x.Func
+	}
+	return ""
+}
+
+func (x *Frame) GetFile() string {
+	if x != nil {
+		return x.File
+	}
+	return ""
+}
+
+func (x *Frame) GetLine() int64 {
+	if x != nil {
+		return x.Line
+	}
+	return 0
+}
+
+func (x *Frame) GetService() string {
+	if x != nil {
+		return x.Service
+	}
+	return ""
+}
+
+func (x *Frame) GetHost() string {
+	if x != nil {
+		return x.Host
+	}
+	return ""
+}
+
+// FieldViolation is a violation of a field of a fail.ValidationError.
+type FieldViolation struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// field is the path of the field, such as "user.emails[0]".
+	Field string `protobuf:"bytes,1,opt,name=field,proto3" json:"field,omitempty"`
+	// constraint is the name of the violated constraint, such as "required".
+	Constraint string `protobuf:"bytes,2,opt,name=constraint,proto3" json:"constraint,omitempty"`
+	// message is a description of the violation.
+	Message       string `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FieldViolation) Reset() {
+	*x = FieldViolation{}
+	mi := &file_fail_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FieldViolation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FieldViolation) ProtoMessage() {}
+
+func (x *FieldViolation) ProtoReflect() protoreflect.Message {
+	mi := &file_fail_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FieldViolation.ProtoReflect.Descriptor instead.
+func (*FieldViolation) Descriptor() ([]byte, []int) {
+	return file_fail_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *FieldViolation) GetField() string {
+	if x != nil {
+		return x.Field
+	}
+	return ""
+}
+
+func (x *FieldViolation) GetConstraint() string {
+	if x != nil {
+		return x.Constraint
+	}
+	return ""
+}
+
+func (x *FieldViolation) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+var File_fail_proto protoreflect.FileDescriptor
+
+const file_fail_proto_rawDesc = "" +
+	"\n" +
+	"\n" +
+	"fail.proto\x12\fsrvc.fail.v1\x1a\x1egoogle/protobuf/duration.proto\x1a\x1cgoogle/protobuf/struct.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xf6\x05\n" +
+	"\x05Error\x12\x18\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type\x12\x1a\n" +
+	"\bmessages\x18\x03 \x03(\tR\bmessages\x12&\n" +
+	"\x04code\x18\x04 \x01(\v2\x12.srvc.fail.v1.CodeR\x04code\x122\n" +
+	"\bseverity\x18\x05 \x01(\x0e2\x16.srvc.fail.v1.SeverityR\bseverity\x12\x12\n" +
+	"\x04tags\x18\x06 \x03(\tR\x04tags\x12/\n" +
+	"\x06params\x18\a \x01(\v2\x17.google.protobuf.StructR\x06params\x12+\n" +
+	"\x06frames\x18\b \x03(\v2\x13.srvc.fail.v1.FrameR\x06frames\x12\x12\n" +
+	"\x04kind\x18\t \x01(\tR\x04kind\x12\x1c\n" +
+	"\tignorable\x18\n" +
+	" \x01(\bR\tignorable\x12\x1c\n" +
+	"\tretryable\x18\v \x01(\bR\tretryable\x12:\n" +
+	"\vretry_after\x18\f \x01(\v2\x19.google.protobuf.DurationR\n" +
+	"retryAfter\x129\n" +
+	"\n" +
+	"created_at\x18\r \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"wrapped_at\x18\x0e \x01(\v2\x1a.google.protobuf.TimestampR\twrappedAt\x12!\n" +
+	"\fgoroutine_id\x18\x0f \x01(\x03R\vgoroutineId\x127\n" +
+	"\x06labels\x18\x10 \x03(\v2\x1f.srvc.fail.v1.Error.LabelsEntryR\x06labels\x12<\n" +
+	"\n" +
+	"violations\x18\x11 \x03(\v2\x1c.srvc.fail.v1.FieldViolationR\n" +
+	"violations\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"s\n" +
+	"\x04Code\x12\x1e\n" +
+	"\tcanonical\x18\x01 \x01(\rH\x00R\tcanonical\x12\x1d\n" +
+	"\tint_value\x18\x02 \x01(\x03H\x00R\bintValue\x12#\n" +
+	"\fstring_value\x18\x03 \x01(\tH\x00R\vstringValueB\a\n" +
+	"\x05value\"q\n" +
+	"\x05Frame\x12\x12\n" +
+	"\x04func\x18\x01 \x01(\tR\x04func\x12\x12\n" +
+	"\x04file\x18\x02 \x01(\tR\x04file\x12\x12\n" +
+	"\x04line\x18\x03 \x01(\x03R\x04line\x12\x18\n" +
+	"\aservice\x18\x04 \x01(\tR\aservice\x12\x12\n" +
+	"\x04host\x18\x05 \x01(\tR\x04host\"`\n" +
+	"\x0eFieldViolation\x12\x14\n" +
+	"\x05field\x18\x01 \x01(\tR\x05field\x12\x1e\n" +
+	"\n" +
+	"constraint\x18\x02 \x01(\tR\n" +
+	"constraint\x12\x18\n" +
+	"\amessage\x18\x03 \x01(\tR\amessage*\x89\x01\n" +
+	"\bSeverity\x12\x18\n" +
+	"\x14SEVERITY_UNSPECIFIED\x10\x00\x12\x12\n" +
+	"\x0eSEVERITY_DEBUG\x10\x01\x12\x11\n" +
+	"\rSEVERITY_INFO\x10\x02\x12\x14\n" +
+	"\x10SEVERITY_WARNING\x10\x03\x12\x12\n" +
+	"\x0eSEVERITY_ERROR\x10\x04\x12\x12\n" +
+	"\x0eSEVERITY_FATAL\x10\x05B Z\x1egithub.com/srvc/fail/v4/failpbb\x06proto3"
+
+var (
+	file_fail_proto_rawDescOnce sync.Once
+	file_fail_proto_rawDescData []byte
+)
+
+func file_fail_proto_rawDescGZIP() []byte {
+	file_fail_proto_rawDescOnce.Do(func() {
+		file_fail_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_fail_proto_rawDesc), len(file_fail_proto_rawDesc)))
+	})
+	return file_fail_proto_rawDescData
+}
+
+var file_fail_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_fail_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_fail_proto_goTypes = []any{
+	(Severity)(0),                 // 0: srvc.fail.v1.Severity
+	(*Error)(nil),                 // 1: srvc.fail.v1.Error
+	(*Code)(nil),                  // 2: srvc.fail.v1.Code
+	(*Frame)(nil),                 // 3: srvc.fail.v1.Frame
+	(*FieldViolation)(nil),        // 4: srvc.fail.v1.FieldViolation
+	nil,                           // 5: srvc.fail.v1.Error.LabelsEntry
+	(*structpb.Struct)(nil),       // 6: google.protobuf.Struct
+	(*durationpb.Duration)(nil),   // 7: google.protobuf.Duration
+	(*timestamppb.Timestamp)(nil), // 8: google.protobuf.Timestamp
+}
+var file_fail_proto_depIdxs = []int32{
+	2, // 0: srvc.fail.v1.Error.code:type_name -> srvc.fail.v1.Code
+	0, // 1: srvc.fail.v1.Error.severity:type_name -> srvc.fail.v1.Severity
+	6, // 2: srvc.fail.v1.Error.params:type_name -> google.protobuf.Struct
+	3, // 3: srvc.fail.v1.Error.frames:type_name -> srvc.fail.v1.Frame
+	7, // 4: srvc.fail.v1.Error.retry_after:type_name -> google.protobuf.Duration
+	8, // 5: srvc.fail.v1.Error.created_at:type_name -> google.protobuf.Timestamp
+	8, // 6: srvc.fail.v1.Error.wrapped_at:type_name -> google.protobuf.Timestamp
+	5, // 7: srvc.fail.v1.Error.labels:type_name -> srvc.fail.v1.Error.LabelsEntry
+	4, // 8: srvc.fail.v1.Error.violations:type_name -> srvc.fail.v1.FieldViolation
+	9, // [9:9] is the sub-list for method output_type
+	9, // [9:9] is the sub-list for method input_type
+	9, // [9:9] is the sub-list for extension type_name
+	9, // [9:9] is the sub-list for extension extendee
+	0, // [0:9] is the sub-list for field type_name
+}
+
+func init() { file_fail_proto_init() }
+func file_fail_proto_init() {
+	if File_fail_proto != nil {
+		return
+	}
+	file_fail_proto_msgTypes[1].OneofWrappers = []any{
+		(*Code_Canonical)(nil),
+		(*Code_IntValue)(nil),
+		(*Code_StringValue)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_fail_proto_rawDesc), len(file_fail_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   5,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_fail_proto_goTypes,
+		DependencyIndexes: file_fail_proto_depIdxs,
+		EnumInfos:         file_fail_proto_enumTypes,
+		MessageInfos:      file_fail_proto_msgTypes,
+	}.Build()
+	File_fail_proto = out.File
+	file_fail_proto_goTypes = nil
+	file_fail_proto_depIdxs = nil
+}
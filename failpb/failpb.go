@@ -0,0 +1,271 @@
+// Package failpb serializes errors of fail in Protocol Buffers to propagate them across services.
+//
+// The server serializes an error with ToProto, and the client rebuilds it with FromProto.
+// Frames of the rebuilt error are marked as remote with their origins, and frames of the client are appended under them
+// when the error is wrapped again.
+//
+//	pb := failpb.ToProto(err, failpb.WithOrigin("users", hostname))
+//
+//	if err := proto.Unmarshal(data, pb); err == nil {
+//		return fail.Wrap(failpb.FromProto(pb), fail.WithMessage("failed to call users service"))
+//	}
+package failpb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative fail.proto
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+
+	"github.com/srvc/fail/v4"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Option configures ToProto
+type Option func(*config)
+
+type config struct {
+	origin fail.Origin
+}
+
+// WithOrigin sets the service name and the host to the local frames.
+// Remote frames keep their origins.
+func WithOrigin(service, host string) Option {
+	return func(c *config) {
+		c.origin = fail.Origin{Service: service, Host: host}
+	}
+}
+
+// ToProto converts the error into the message.
+// Params that can't be represented in google.protobuf.Struct are converted into strings.
+// It returns nil if err is nil.
+func ToProto(err error, opts ...Option) *Error {
+	if err == nil {
+		return nil
+	}
+
+	cfg := &config{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	failErr := fail.Unwrap(err)
+	if failErr == nil {
+		failErr = &fail.Error{Err: err}
+	}
+
+	pb := &Error{
+		Message:     failErr.Err.Error(),
+		Type:        fail.RootTypeName(failErr.Err),
+		Messages:    failErr.Messages,
+		Code:        toCode(failErr.Code),
+		Severity:    Severity(failErr.Severity),
+		Tags:        failErr.Tags,
+		Params:      toStruct(failErr.Params),
+		Ignorable:   failErr.Ignorable,
+		Retryable:   failErr.Retryable,
+		GoroutineId: failErr.GoroutineID,
+		Labels:      failErr.Labels,
+	}
+	if failErr.Kind != nil {
+		pb.Kind = failErr.Kind.ID()
+	}
+	if failErr.RetryAfter > 0 {
+		pb.RetryAfter = durationpb.New(failErr.RetryAfter)
+	}
+	if !failErr.CreatedAt.IsZero() {
+		pb.CreatedAt = timestamppb.New(failErr.CreatedAt)
+	}
+	if !failErr.WrappedAt.IsZero() {
+		pb.WrappedAt = timestamppb.New(failErr.WrappedAt)
+	}
+	for _, v := range fail.FieldViolations(failErr) {
+		pb.Violations = append(pb.Violations, &FieldViolation{Field: v.Field, Constraint: v.Constraint, Message: v.Message})
+	}
+	for _, f := range failErr.StackTrace {
+		origin := f.Origin
+		if !f.Remote {
+			origin = cfg.origin
+		}
+		pb.Frames = append(pb.Frames, &Frame{
+			Func:    f.Func,
+			File:    f.File,
+			Line:    f.Line,
+			Service: origin.Service,
+			Host:    origin.Host,
+		})
+	}
+	return pb
+}
+
+// FromProto rebuilds an error from the message.
+// The root error is rebuilt as *fail.DecodedError, or *fail.ValidationError if it has violations, the kind is rebuilt from its ID,
+// and every frame is marked as remote with its origin.
+// It returns nil if pb is nil.
+func FromProto(pb *Error) error {
+	if pb == nil {
+		return nil
+	}
+
+	failErr := &fail.Error{
+		Err:         &fail.DecodedError{Type: pb.GetType(), Message: pb.GetMessage()},
+		Messages:    pb.GetMessages(),
+		Code:        fromCode(pb.GetCode()),
+		Severity:    fail.Severity(pb.GetSeverity()),
+		Tags:        pb.GetTags(),
+		Params:      fromStruct(pb.GetParams()),
+		Ignorable:   pb.GetIgnorable(),
+		Retryable:   pb.GetRetryable(),
+		GoroutineID: pb.GetGoroutineId(),
+		Labels:      pb.GetLabels(),
+	}
+	if violations := pb.GetViolations(); len(violations) > 0 {
+		verr := &fail.ValidationError{}
+		for _, v := range violations {
+			verr.Violations = append(verr.Violations, fail.FieldViolation{Field: v.GetField(), Constraint: v.GetConstraint(), Message: v.GetMessage()})
+		}
+		failErr.Err = verr
+	}
+	if pb.GetCreatedAt() != nil {
+		failErr.CreatedAt = pb.GetCreatedAt().AsTime()
+	}
+	if pb.GetWrappedAt() != nil {
+		failErr.WrappedAt = pb.GetWrappedAt().AsTime()
+	}
+	if pb.GetKind() != "" {
+		failErr.Kind = fail.Define(pb.GetKind())
+	}
+	if pb.GetRetryAfter() != nil {
+		failErr.RetryAfter = pb.GetRetryAfter().AsDuration()
+	}
+	for _, f := range pb.GetFrames() {
+		failErr.StackTrace = append(failErr.StackTrace, fail.Frame{
+			Func:   f.GetFunc(),
+			File:   f.GetFile(),
+			Line:   f.GetLine(),
+			Remote: true,
+			Origin: fail.Origin{Service: f.GetService(), Host: f.GetHost()},
+		})
+	}
+	return failErr
+}
+
+// toCode converts the code of an error.
+// Codes other than fail.CanonicalCode, integers and strings are converted into strings.
+func toCode(code interface{}) *Code {
+	switch c := code.(type) {
+	case nil:
+		return nil
+	case fail.CanonicalCode:
+		return &Code{Value: &Code_Canonical{Canonical: uint32(c)}}
+	case int:
+		return &Code{Value: &Code_IntValue{IntValue: int64(c)}}
+	case int32:
+		return &Code{Value: &Code_IntValue{IntValue: int64(c)}}
+	case int64:
+		return &Code{Value: &Code_IntValue{IntValue: c}}
+	case string:
+		return &Code{Value: &Code_StringValue{StringValue: c}}
+	}
+	return &Code{Value: &Code_StringValue{StringValue: fmt.Sprint(code)}}
+}
+
+// fromCode converts the code into fail.CanonicalCode, int or string
+func fromCode(code *Code) interface{} {
+	switch c := code.GetValue().(type) {
+	case *Code_Canonical:
+		return fail.CanonicalCode(c.Canonical)
+	case *Code_IntValue:
+		return int(c.IntValue)
+	case *Code_StringValue:
+		return c.StringValue
+	}
+	return nil
+}
+
+// toStruct converts the params into google.protobuf.Struct
+func toStruct(params fail.H) *structpb.Struct {
+	if len(params) == 0 {
+		return nil
+	}
+
+	s := &structpb.Struct{Fields: make(map[string]*structpb.Value, len(params))}
+	for k, v := range params {
+		s.Fields[k] = toValue(v)
+	}
+	return s
+}
+
+// toValue converts the value into google.protobuf.Value.
+// Nested params, maps with string keys, slices and arrays are converted recursively,
+// and values that google.protobuf.Value can't hold are converted into strings.
+func toValue(v interface{}) *structpb.Value {
+	switch v := v.(type) {
+	case nil, []byte:
+	case fail.H:
+		return toValue(map[string]interface{}(v))
+	default:
+		rv := reflect.ValueOf(v)
+		switch rv.Kind() {
+		case reflect.Map:
+			if rv.Type().Key().Kind() != reflect.String {
+				break
+			}
+			s := &structpb.Struct{Fields: make(map[string]*structpb.Value, rv.Len())}
+			iter := rv.MapRange()
+			for iter.Next() {
+				s.Fields[iter.Key().String()] = toValue(iter.Value().Interface())
+			}
+			return structpb.NewStructValue(s)
+		case reflect.Slice, reflect.Array:
+			l := &structpb.ListValue{Values: make([]*structpb.Value, rv.Len())}
+			for i := range l.Values {
+				l.Values[i] = toValue(rv.Index(i).Interface())
+			}
+			return structpb.NewListValue(l)
+		}
+	}
+
+	value, err := structpb.NewValue(v)
+	if err != nil {
+		return structpb.NewStringValue(fmt.Sprint(v))
+	}
+	return value
+}
+
+// fromStruct converts google.protobuf.Struct into params
+func fromStruct(s *structpb.Struct) fail.H {
+	if len(s.GetFields()) == 0 {
+		return nil
+	}
+
+	params := fail.H(s.AsMap())
+	for k, v := range params {
+		params[k] = fromValue(v)
+	}
+	return params
+}
+
+// fromValue converts integral numbers in the value into int, since google.protobuf.Struct has only float64
+func fromValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, e := range v {
+			v[k] = fromValue(e)
+		}
+		return v
+	case []interface{}:
+		for i, e := range v {
+			v[i] = fromValue(e)
+		}
+		return v
+	case float64:
+		if v == math.Trunc(v) && math.Abs(v) <= 1<<53 {
+			return int(v)
+		}
+	}
+	return v
+}
@@ -0,0 +1,177 @@
+package failpb
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/srvc/fail/v4"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestToProto(t *testing.T) {
+	kind := fail.Define("users.not_found")
+	err := fail.Wrap(kind.New("user not found"),
+		fail.WithMessage("failed to find user"),
+		fail.WithCode(fail.CodeNotFound),
+		fail.WithSeverity(fail.SeverityWarning),
+		fail.WithTags("db"),
+		fail.WithParams(fail.H{"id": 1, "name": "foo", "ratio": 0.5, "nested": map[string]interface{}{"n": 2}, "duration": time.Second}),
+		fail.WithRetryAfter(time.Second),
+	)
+
+	pb := ToProto(err)
+	assert.Equal(t, "user not found", pb.GetMessage())
+	assert.Equal(t, "*errors.errorString", pb.GetType())
+	assert.Equal(t, []string{"failed to find user"}, pb.GetMessages())
+	assert.Equal(t, uint32(fail.CodeNotFound), pb.GetCode().GetCanonical())
+	assert.Equal(t, Severity_SEVERITY_WARNING, pb.GetSeverity())
+	assert.Equal(t, []string{"db"}, pb.GetTags())
+	assert.Equal(t, "1s", pb.GetParams().GetFields()["duration"].GetStringValue())
+	assert.Equal(t, "users.not_found", pb.GetKind())
+	assert.True(t, pb.GetRetryable())
+	assert.Equal(t, time.Second, pb.GetRetryAfter().AsDuration())
+	assert.Len(t, pb.GetFrames(), len(fail.Unwrap(err).StackTrace))
+	assert.Equal(t, "TestToProto", pb.GetFrames()[0].GetFunc())
+
+	t.Run("codes", func(t *testing.T) {
+		assert.Equal(t, int64(404), ToProto(fail.Wrap(errors.New("origin"), fail.WithCode(404))).GetCode().GetIntValue())
+		assert.Equal(t, "not_found", ToProto(fail.Wrap(errors.New("origin"), fail.WithCode("not_found"))).GetCode().GetStringValue())
+		assert.Equal(t, "1.5", ToProto(fail.Wrap(errors.New("origin"), fail.WithCode(1.5))).GetCode().GetStringValue())
+		assert.Nil(t, ToProto(errors.New("origin")).GetCode())
+	})
+
+	t.Run("nil", func(t *testing.T) {
+		assert.Nil(t, ToProto(nil))
+	})
+}
+
+func TestFromProto(t *testing.T) {
+	kind := fail.Define("users.not_found")
+	remote := fail.Wrap(kind.New("user not found"),
+		fail.WithMessage("failed to find user"),
+		fail.WithCode(fail.CodeNotFound),
+		fail.WithIgnorable(),
+		fail.WithTags("db"),
+		fail.WithParams(fail.H{"id": 1, "ratio": 0.5, "nested": map[string]interface{}{"n": 2}}),
+	)
+
+	data, err := proto.Marshal(ToProto(remote))
+	assert.NoError(t, err)
+	var pb Error
+	assert.NoError(t, proto.Unmarshal(data, &pb))
+
+	err = FromProto(&pb)
+	failErr := fail.Unwrap(err)
+	assert.Equal(t, &fail.DecodedError{Type: "*errors.errorString", Message: "user not found"}, failErr.Err)
+	assert.Equal(t, "failed to find user: user not found", err.Error())
+	assert.Equal(t, fail.CodeNotFound, failErr.Code)
+	assert.Equal(t, fail.SeverityInfo, failErr.Severity)
+	assert.True(t, failErr.Ignorable)
+	assert.Equal(t, []string{"db"}, failErr.Tags)
+	assert.Equal(t, fail.H{"id": 1, "ratio": 0.5, "nested": map[string]interface{}{"n": 2}}, failErr.Params)
+	assert.True(t, errors.Is(err, kind))
+
+	remoteStack := fail.Unwrap(remote).StackTrace
+	assert.Len(t, failErr.StackTrace, len(remoteStack))
+	for i, f := range failErr.StackTrace {
+		assert.True(t, f.Remote)
+		assert.Equal(t, remoteStack[i].Func, f.Func)
+	}
+
+	t.Run("appended under the local stack", func(t *testing.T) {
+		wrapped := fail.Unwrap(fail.Wrap(err))
+		assert.Equal(t, failErr.StackTrace, wrapped.StackTrace[:len(failErr.StackTrace)])
+		local := wrapped.StackTrace[len(failErr.StackTrace):]
+		assert.NotEmpty(t, local)
+		assert.False(t, local[0].Remote)
+		assert.Equal(t, "TestFromProto.func1", local[0].Func)
+	})
+
+	t.Run("origins", func(t *testing.T) {
+		users := FromProto(ToProto(remote, WithOrigin("users", "users-1")))
+		gateway := FromProto(ToProto(fail.Wrap(users), WithOrigin("gateway", "gateway-1")))
+
+		st := fail.Unwrap(gateway).StackTrace
+		assert.Equal(t, fail.Origin{Service: "users", Host: "users-1"}, st[0].Origin)
+		assert.Equal(t, fail.Origin{Service: "gateway", Host: "gateway-1"}, st[len(st)-1].Origin)
+		assert.Equal(t, "--- remote (users) ---", st.Separator(0))
+		assert.Empty(t, st.Local())
+	})
+
+	t.Run("occurrence", func(t *testing.T) {
+		createdAt := time.Date(2024, 1, 2, 3, 4, 5, 6, time.UTC)
+		remote := &fail.Error{
+			Err:         errors.New("origin"),
+			CreatedAt:   createdAt,
+			WrappedAt:   createdAt.Add(time.Second),
+			GoroutineID: 42,
+			Labels:      map[string]string{"endpoint": "/users"},
+		}
+
+		failErr := fail.Unwrap(roundTrip(t, remote))
+		assert.Equal(t, remote.CreatedAt, failErr.CreatedAt)
+		assert.Equal(t, remote.WrappedAt, failErr.WrappedAt)
+		assert.Equal(t, int64(42), failErr.GoroutineID)
+		assert.Equal(t, map[string]string{"endpoint": "/users"}, failErr.Labels)
+	})
+
+	t.Run("nested params", func(t *testing.T) {
+		err := fail.Wrap(errors.New("origin"),
+			fail.WithParamPath("user.id", 1),
+			fail.WithParam("tags", []string{"a", "b"}),
+			fail.WithParam("ids", [2]int{1, 2}),
+			fail.WithParam("meta", map[string]string{"source": "api"}),
+			fail.WithParam("at", time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)),
+		)
+
+		failErr := fail.Unwrap(roundTrip(t, err))
+		assert.Equal(t, fail.H{
+			"user": map[string]interface{}{"id": 1},
+			"tags": []interface{}{"a", "b"},
+			"ids":  []interface{}{1, 2},
+			"meta": map[string]interface{}{"source": "api"},
+			"at":   "2024-01-02 03:04:05 +0000 UTC",
+		}, failErr.Params)
+
+		id, ok := failErr.Params.Lookup("user.id")
+		assert.True(t, ok)
+		assert.Equal(t, 1, id)
+	})
+
+	t.Run("validation", func(t *testing.T) {
+		v := fail.NewValidation()
+		v.Add("name", "required", "must not be empty")
+		v.Add("age", "min", "must be at least 0")
+
+		err := roundTrip(t, v.Err())
+		var verr *fail.ValidationError
+		if assert.True(t, errors.As(err, &verr)) {
+			assert.Equal(t, []fail.FieldViolation{
+				{Field: "name", Constraint: "required", Message: "must not be empty"},
+				{Field: "age", Constraint: "min", Message: "must be at least 0"},
+			}, verr.Violations)
+		}
+		assert.Equal(t, fail.CodeInvalidArgument, fail.Unwrap(err).Code)
+		assert.Equal(t, v.Err().Error(), err.Error())
+	})
+
+	t.Run("nil", func(t *testing.T) {
+		assert.Nil(t, FromProto(nil))
+	})
+}
+
+// roundTrip serializes the error through the wire format and rebuilds it
+func roundTrip(t *testing.T, err error) error {
+	t.Helper()
+	data, merr := proto.Marshal(ToProto(err))
+	if merr != nil {
+		t.Fatal(merr)
+	}
+	var pb Error
+	if uerr := proto.Unmarshal(data, &pb); uerr != nil {
+		t.Fatal(uerr)
+	}
+	return FromProto(&pb)
+}
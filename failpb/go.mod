@@ -0,0 +1,16 @@
+module github.com/srvc/fail/v4/failpb
+
+go 1.23.0
+
+require (
+	github.com/srvc/fail/v4 v4.0.0
+	github.com/stretchr/testify v1.12.1
+	google.golang.org/protobuf v1.36.12
+)
+
+require (
+	github.com/pkg/errors v0.8.1 // indirect
+	go.yaml.in/yaml/v3 v3.0.5 // indirect
+)
+
+replace github.com/srvc/fail/v4 => ../
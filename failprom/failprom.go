@@ -0,0 +1,258 @@
+// Package failprom exposes Prometheus metrics of fail errors.
+package failprom
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/srvc/fail/v4"
+)
+
+const (
+	// OverflowLabel is the label value used for values which are not allowed
+	// or exceed the cardinality limit
+	OverflowLabel = "other"
+
+	defaultNamespace      = "fail"
+	defaultMaxLabelValues = 100
+)
+
+var labelNames = []string{"code", "tag", "ignorable", "origin"}
+
+// Collector is a prometheus.Collector that counts observed errors
+// by code, tag, ignorability and origin.
+// The origin is the function of the innermost in-app frame of the stack trace.
+type Collector struct {
+	counter *prometheus.CounterVec
+
+	inAppPrefixes []string
+	codes         *labelGuard
+	tags          *labelGuard
+	tagRoutes     []tagRoute
+	filter        fail.Matcher
+	origins       *labelGuard
+}
+
+var _ prometheus.Collector = (*Collector)(nil)
+
+// Option configures a Collector
+type Option func(*config)
+
+type config struct {
+	namespace      string
+	inAppPrefixes  []string
+	allowedCodes   []string
+	allowedTags    []string
+	tagRoutes      []tagRoute
+	filter         fail.Matcher
+	maxLabelValues int
+}
+
+type tagRoute struct {
+	name  string
+	query *fail.TagQuery
+}
+
+// WithNamespace sets the namespace of the metric. It defaults to "fail".
+func WithNamespace(namespace string) Option {
+	return func(c *config) {
+		c.namespace = namespace
+	}
+}
+
+// WithInAppPrefixes sets prefixes of files or functions that belong to the application, as fail.Frame.InApp.
+// Without prefixes, the innermost frame is used as the origin.
+func WithInAppPrefixes(prefixes ...string) Option {
+	return func(c *config) {
+		c.inAppPrefixes = append(c.inAppPrefixes, prefixes...)
+	}
+}
+
+// WithAllowedCodes restricts the values of the code label.
+// Codes are compared in their fmt.Sprint representation.
+func WithAllowedCodes(codes ...interface{}) Option {
+	return func(c *config) {
+		for _, code := range codes {
+			c.allowedCodes = append(c.allowedCodes, fmt.Sprint(code))
+		}
+	}
+}
+
+// WithAllowedTags restricts the values of the tag label.
+func WithAllowedTags(tags ...string) Option {
+	return func(c *config) {
+		c.allowedTags = append(c.allowedTags, tags...)
+	}
+}
+
+// WithTagRoute sets the tag label to the name for errors whose tags match the query,
+// such as WithTagRoute("storage", fail.MustParseTagQuery("db || cache")).
+// Routes are tried in order before the tags of the error.
+func WithTagRoute(name string, query *fail.TagQuery) Option {
+	return func(c *config) {
+		c.tagRoutes = append(c.tagRoutes, tagRoute{name: name, query: query})
+	}
+}
+
+// WithFilter makes the collector observe only errors that match the matcher,
+// such as WithFilter(fail.MustParseMatcher("!ignorable")).
+func WithFilter(m fail.Matcher) Option {
+	return func(c *config) {
+		c.filter = m
+	}
+}
+
+// WithMaxLabelValues limits the number of distinct values per label
+// that are not restricted by an allow-list. It defaults to 100.
+func WithMaxLabelValues(n int) Option {
+	return func(c *config) {
+		c.maxLabelValues = n
+	}
+}
+
+// NewCollector creates a new Collector
+func NewCollector(opts ...Option) *Collector {
+	cfg := &config{
+		namespace:      defaultNamespace,
+		maxLabelValues: defaultMaxLabelValues,
+	}
+	for _, f := range opts {
+		f(cfg)
+	}
+
+	return &Collector{
+		counter: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: cfg.namespace,
+			Name:      "errors_total",
+			Help:      "Number of errors observed, partitioned by code, tag, ignorability and origin.",
+		}, labelNames),
+		inAppPrefixes: cfg.inAppPrefixes,
+		codes:         newLabelGuard(cfg.allowedCodes, cfg.maxLabelValues),
+		tags:          newLabelGuard(cfg.allowedTags, cfg.maxLabelValues),
+		tagRoutes:     cfg.tagRoutes,
+		filter:        cfg.filter,
+		origins:       newLabelGuard(nil, cfg.maxLabelValues),
+	}
+}
+
+// Describe implements prometheus.Collector
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	c.counter.Describe(ch)
+}
+
+// Collect implements prometheus.Collector
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.counter.Collect(ch)
+}
+
+// Observe increments the counter for the error.
+// It does nothing if err is nil or doesn't match the filter.
+func (c *Collector) Observe(err error) {
+	if err == nil || (c.filter != nil && !c.filter(err)) {
+		return
+	}
+
+	failErr := fail.Unwrap(err)
+	if failErr == nil {
+		failErr = &fail.Error{Err: err}
+	}
+
+	c.counter.WithLabelValues(
+		c.codeLabel(failErr),
+		c.tagLabel(failErr),
+		strconv.FormatBool(failErr.Ignorable),
+		c.originLabel(failErr),
+	).Inc()
+}
+
+func (c *Collector) codeLabel(err *fail.Error) string {
+	if err.Code == nil {
+		return ""
+	}
+	return c.codes.value(fmt.Sprint(err.Code))
+}
+
+// tagLabel returns the name of the first matching route, or the first allowed tag of the error
+func (c *Collector) tagLabel(err *fail.Error) string {
+	for _, r := range c.tagRoutes {
+		if r.query.Match(err.Tags) {
+			return r.name
+		}
+	}
+	if len(err.Tags) == 0 {
+		return ""
+	}
+	for _, tag := range err.Tags {
+		if c.tags.allows(tag) {
+			return c.tags.value(tag)
+		}
+	}
+	return c.tags.value(err.Tags[0])
+}
+
+func (c *Collector) originLabel(err *fail.Error) string {
+	for _, f := range err.StackTrace {
+		if f.InApp(c.inAppPrefixes...) {
+			return c.origins.value(f.Func)
+		}
+	}
+	return ""
+}
+
+// labelGuard bounds the cardinality of a label
+type labelGuard struct {
+	allowed map[string]struct{}
+	max     int
+
+	mu   sync.Mutex
+	seen map[string]struct{}
+}
+
+func newLabelGuard(allowed []string, max int) *labelGuard {
+	g := &labelGuard{
+		max:  max,
+		seen: map[string]struct{}{},
+	}
+	if allowed != nil {
+		g.allowed = make(map[string]struct{}, len(allowed))
+		for _, v := range allowed {
+			g.allowed[v] = struct{}{}
+		}
+	}
+	return g
+}
+
+// allows reports whether the value is in the allow-list.
+// It always returns true if the guard has no allow-list.
+func (g *labelGuard) allows(v string) bool {
+	if g.allowed == nil {
+		return true
+	}
+	_, ok := g.allowed[v]
+	return ok
+}
+
+// value returns the label value for v, or OverflowLabel
+// if v is not allowed or too many distinct values have been seen
+func (g *labelGuard) value(v string) string {
+	if g.allowed != nil {
+		if g.allows(v) {
+			return v
+		}
+		return OverflowLabel
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if _, ok := g.seen[v]; ok {
+		return v
+	}
+	if len(g.seen) >= g.max {
+		return OverflowLabel
+	}
+	g.seen[v] = struct{}{}
+	return v
+}
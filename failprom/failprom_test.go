@@ -0,0 +1,128 @@
+package failprom
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/srvc/fail/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+var testStackTrace = fail.StackTrace{
+	{Func: "Query", File: "github.com/lib/db/query.go", Line: 10},
+	{Func: "FindUser", File: "github.com/example/app/user.go", Line: 20},
+	{Func: "main", File: "github.com/example/app/main.go", Line: 30},
+}
+
+func TestCollector(t *testing.T) {
+	t.Run("labels", func(t *testing.T) {
+		c := NewCollector(WithInAppPrefixes("github.com/example/app/"))
+
+		c.Observe(nil)
+		c.Observe(errors.New("origin"))
+		c.Observe(&fail.Error{
+			Err:        errors.New("origin"),
+			Code:       404,
+			Ignorable:  true,
+			Tags:       []string{"db", "user"},
+			StackTrace: testStackTrace,
+		})
+		c.Observe(&fail.Error{
+			Err:        errors.New("origin"),
+			Code:       404,
+			Ignorable:  true,
+			Tags:       []string{"db"},
+			StackTrace: testStackTrace,
+		})
+
+		assertMetrics(t, c, "fail_errors_total", `
+			fail_errors_total{code="",ignorable="false",origin="",tag=""} 1
+			fail_errors_total{code="404",ignorable="true",origin="FindUser",tag="db"} 2
+		`)
+	})
+
+	t.Run("allow-lists", func(t *testing.T) {
+		c := NewCollector(
+			WithNamespace("app"),
+			WithAllowedCodes(404),
+			WithAllowedTags("user"),
+		)
+
+		c.Observe(&fail.Error{Err: errors.New("origin"), Code: 404, Tags: []string{"db", "user"}})
+		c.Observe(&fail.Error{Err: errors.New("origin"), Code: 500, Tags: []string{"db"}})
+
+		assertMetrics(t, c, "app_errors_total", `
+			app_errors_total{code="404",ignorable="false",origin="",tag="user"} 1
+			app_errors_total{code="other",ignorable="false",origin="",tag="other"} 1
+		`)
+	})
+
+	t.Run("tag routes", func(t *testing.T) {
+		c := NewCollector(
+			WithAllowedTags("user"),
+			WithTagRoute("storage", fail.MustParseTagQuery("db || cache")),
+			WithTagRoute("upstream", fail.MustParseTagQuery("remote:*")),
+		)
+
+		c.Observe(&fail.Error{Err: errors.New("origin"), Tags: []string{"user", "cache"}})
+		c.Observe(&fail.Error{Err: errors.New("origin"), Tags: []string{"remote:billing", "db"}})
+		c.Observe(&fail.Error{Err: errors.New("origin"), Tags: []string{"remote:billing"}})
+		c.Observe(&fail.Error{Err: errors.New("origin"), Tags: []string{"user"}})
+
+		assertMetrics(t, c, "fail_errors_total", `
+			fail_errors_total{code="",ignorable="false",origin="",tag="storage"} 2
+			fail_errors_total{code="",ignorable="false",origin="",tag="upstream"} 1
+			fail_errors_total{code="",ignorable="false",origin="",tag="user"} 1
+		`)
+	})
+
+	t.Run("filter", func(t *testing.T) {
+		c := NewCollector(WithFilter(fail.MustParseMatcher("!ignorable && !code:NotFound")))
+
+		c.Observe(&fail.Error{Err: errors.New("origin"), Code: 500})
+		c.Observe(&fail.Error{Err: errors.New("origin"), Code: 500, Ignorable: true})
+		c.Observe(&fail.Error{Err: errors.New("origin"), Code: fail.CodeNotFound})
+
+		assertMetrics(t, c, "fail_errors_total", `
+			fail_errors_total{code="500",ignorable="false",origin="",tag=""} 1
+		`)
+	})
+
+	t.Run("max label values", func(t *testing.T) {
+		c := NewCollector(WithMaxLabelValues(1))
+
+		c.Observe(&fail.Error{Err: errors.New("origin"), Code: 400})
+		c.Observe(&fail.Error{Err: errors.New("origin"), Code: 404})
+		c.Observe(&fail.Error{Err: errors.New("origin"), Code: 500})
+		c.Observe(&fail.Error{Err: errors.New("origin"), Code: 400})
+
+		assertMetrics(t, c, "fail_errors_total", `
+			fail_errors_total{code="400",ignorable="false",origin="",tag=""} 2
+			fail_errors_total{code="other",ignorable="false",origin="",tag=""} 2
+		`)
+	})
+
+	t.Run("registry", func(t *testing.T) {
+		reg := prometheus.NewPedanticRegistry()
+		c := NewCollector()
+		assert.NoError(t, reg.Register(c))
+
+		c.Observe(fail.New("origin"))
+
+		n, err := testutil.GatherAndCount(reg, "fail_errors_total")
+		assert.NoError(t, err)
+		assert.Equal(t, 1, n)
+	})
+}
+
+func assertMetrics(t *testing.T, c prometheus.Collector, name, metrics string) {
+	t.Helper()
+	expected := `
+		# HELP ` + name + ` Number of errors observed, partitioned by code, tag, ignorability and origin.
+		# TYPE ` + name + ` counter
+	` + metrics
+	assert.NoError(t, testutil.CollectAndCompare(c, strings.NewReader(expected)))
+}
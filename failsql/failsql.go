@@ -0,0 +1,107 @@
+// Package failsql classifies errors of database/sql and database drivers
+// into canonical codes, retryability and tags.
+//
+//	row := db.QueryRowContext(ctx, "SELECT name FROM users WHERE id = ?", id)
+//	if err := row.Scan(&name); err != nil {
+//		return fail.Wrap(err, failsql.WithClassification())
+//	}
+//
+// Importing the package also registers Classify with fail.RegisterClassifier,
+// so that fail.WithClassification classifies errors of databases,
+// and the errors of database/sql with fail.RegisterMatchTarget, such as "root:sql.ErrNoRows".
+package failsql
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"sync"
+
+	"github.com/srvc/fail/v4"
+)
+
+// TagDB is the tag annotated to every classified error
+const TagDB = "db"
+
+// Classifier returns an annotator for the error of a database, or nil if it doesn't know the error
+type Classifier = fail.Classifier
+
+var (
+	classifiersMu sync.RWMutex
+	classifiers   = []Classifier{ClassifyPostgres}
+)
+
+func init() {
+	fail.RegisterClassifier(Classify)
+	fail.RegisterMatchTarget("sql.ErrNoRows", sql.ErrNoRows)
+	fail.RegisterMatchTarget("sql.ErrTxDone", sql.ErrTxDone)
+	fail.RegisterMatchTarget("sql.ErrConnDone", sql.ErrConnDone)
+}
+
+// RegisterClassifier registers a classifier of a database driver.
+// Classifiers are consulted in the order they were registered, after ClassifyPostgres
+// and before the classification of errors of database/sql.
+func RegisterClassifier(c Classifier) {
+	classifiersMu.Lock()
+	defer classifiersMu.Unlock()
+	classifiers = append(classifiers, c)
+}
+
+// Classify returns an annotator for the error from the registered classifiers
+// or the classification of errors of database/sql, or nil if the error is unknown.
+func Classify(err error) fail.Annotator {
+	if err == nil {
+		return nil
+	}
+
+	classifiersMu.RLock()
+	cs := classifiers
+	classifiersMu.RUnlock()
+
+	for _, c := range cs {
+		if f := c(err); f != nil {
+			return f
+		}
+	}
+	return classifyStd(err)
+}
+
+// WithClassification annotates an error returned by a database with the classification of its root error.
+// Errors unknown to Classify, such as context.DeadlineExceeded, are classified by fail.Classify and tagged with TagDB.
+// Annotators after it override the classification.
+func WithClassification() fail.Annotator {
+	return func(err *fail.Error) {
+		if f := Classify(err.Err); f != nil {
+			f(err)
+			return
+		}
+		if f := fail.Classify(err.Err); f != nil {
+			f(err)
+			fail.WithTags(TagDB)(err)
+		}
+	}
+}
+
+// classifyStd classifies errors of database/sql and database/sql/driver
+func classifyStd(err error) fail.Annotator {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return annotate(fail.CodeNotFound, false)
+	case errors.Is(err, sql.ErrTxDone), errors.Is(err, sql.ErrConnDone):
+		return annotate(fail.CodeInternal, false)
+	case errors.Is(err, driver.ErrBadConn):
+		return annotate(fail.CodeUnavailable, true)
+	}
+	return nil
+}
+
+// annotate returns an annotator of the code, the retryability and the tags with TagDB
+func annotate(code fail.CanonicalCode, retryable bool, tags ...string) fail.Annotator {
+	return func(err *fail.Error) {
+		fail.WithCode(code)(err)
+		if retryable {
+			fail.WithRetryable()(err)
+		}
+		fail.WithTags(append([]string{TagDB}, tags...)...)(err)
+	}
+}
@@ -0,0 +1,163 @@
+package failsql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/srvc/fail/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeDriver is a driver whose connections fail with the error of the DSN
+type fakeDriver struct {
+	errs map[string]error
+}
+
+func (d *fakeDriver) Open(dsn string) (driver.Conn, error) {
+	return &fakeConn{err: d.errs[dsn]}, nil
+}
+
+type fakeConn struct {
+	err error
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("not supported")
+}
+func (c *fakeConn) Close() error              { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error) { return nil, errors.New("not supported") }
+
+func (c *fakeConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	return &fakeRows{}, nil
+}
+
+func (c *fakeConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	return driver.RowsAffected(0), nil
+}
+
+// fakeRows is empty rows
+type fakeRows struct{}
+
+func (r *fakeRows) Columns() []string              { return []string{"name"} }
+func (r *fakeRows) Close() error                   { return nil }
+func (r *fakeRows) Next(dest []driver.Value) error { return io.EOF }
+
+type pgError struct {
+	code string
+}
+
+func (e *pgError) Error() string    { return "pq: " + e.code }
+func (e *pgError) SQLState() string { return e.code }
+
+type mysqlError struct {
+	number uint16
+}
+
+func (e *mysqlError) Error() string { return fmt.Sprintf("Error %d", e.number) }
+
+var testDriver = &fakeDriver{errs: map[string]error{
+	"unique":     &pgError{code: "23505"},
+	"fk":         &pgError{code: "23503"},
+	"serialize":  &pgError{code: "40001"},
+	"connection": &pgError{code: "08006"},
+	"unknown":    &pgError{code: "XX000"},
+	"badconn":    driver.ErrBadConn,
+	"mysql":      &mysqlError{number: 1062},
+	"timeout":    fmt.Errorf("query: %w", context.DeadlineExceeded),
+}}
+
+func init() {
+	sql.Register("failsql-fake", testDriver)
+	RegisterClassifier(MySQLClassifier(func(err error) (uint16, bool) {
+		var me *mysqlError
+		if errors.As(err, &me) {
+			return me.number, true
+		}
+		return 0, false
+	}))
+}
+
+func query(t *testing.T, dsn string) error {
+	db, err := sql.Open("failsql-fake", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	var name string
+	return db.QueryRowContext(context.Background(), "SELECT name FROM users").Scan(&name)
+}
+
+func TestWithClassification(t *testing.T) {
+	cases := []struct {
+		dsn           string
+		wantCode      interface{}
+		wantRetryable bool
+		wantTags      []string
+		wantParams    fail.H
+	}{
+		{dsn: "norows", wantCode: fail.CodeNotFound, wantTags: []string{"db"}},
+		{dsn: "unique", wantCode: fail.CodeAlreadyExists, wantTags: []string{"db", "unique_violation"}, wantParams: fail.H{"sqlstate": "23505"}},
+		{dsn: "fk", wantCode: fail.CodeFailedPrecondition, wantTags: []string{"db", "foreign_key_violation"}, wantParams: fail.H{"sqlstate": "23503"}},
+		{dsn: "serialize", wantCode: fail.CodeAborted, wantRetryable: true, wantTags: []string{"db", "serialization_failure"}, wantParams: fail.H{"sqlstate": "40001"}},
+		{dsn: "connection", wantCode: fail.CodeUnavailable, wantRetryable: true, wantTags: []string{"db"}, wantParams: fail.H{"sqlstate": "08006"}},
+		{dsn: "unknown", wantCode: fail.CodeInternal, wantTags: []string{"db"}, wantParams: fail.H{"sqlstate": "XX000"}},
+		{dsn: "badconn", wantCode: fail.CodeUnavailable, wantRetryable: true, wantTags: []string{"db"}},
+		{dsn: "mysql", wantCode: fail.CodeAlreadyExists, wantTags: []string{"db", "unique_violation"}, wantParams: fail.H{"mysql_error_number": 1062}},
+		{dsn: "timeout", wantCode: fail.CodeDeadlineExceeded, wantRetryable: true, wantTags: []string{"db"}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.dsn, func(t *testing.T) {
+			err := fail.Unwrap(fail.Wrap(query(t, c.dsn), WithClassification()))
+
+			assert.Equal(t, c.wantCode, err.Code)
+			assert.Equal(t, c.wantRetryable, err.Retryable)
+			assert.Equal(t, c.wantTags, err.Tags)
+			assert.Equal(t, c.wantParams, err.Params)
+		})
+	}
+
+	t.Run("overridden", func(t *testing.T) {
+		err := fail.Unwrap(fail.Wrap(query(t, "norows"), WithClassification(), fail.WithCode(404)))
+		assert.Equal(t, 404, err.Code)
+	})
+
+	t.Run("unknown error", func(t *testing.T) {
+		err := fail.Unwrap(fail.Wrap(errors.New("origin"), WithClassification()))
+		assert.Nil(t, err.Code)
+		assert.Empty(t, err.Tags)
+	})
+}
+
+func TestClassify(t *testing.T) {
+	assert.Nil(t, Classify(nil))
+	assert.Nil(t, Classify(errors.New("origin")))
+	assert.NotNil(t, Classify(sql.ErrTxDone))
+	assert.Nil(t, Classify(context.Canceled))
+}
+
+func TestRegistered(t *testing.T) {
+	err := fail.Unwrap(fail.Wrap(query(t, "unique"), fail.WithClassification()))
+	assert.Equal(t, fail.CodeAlreadyExists, err.Code)
+	assert.Equal(t, []string{"db", "unique_violation"}, err.Tags)
+
+	err = fail.Unwrap(fail.Wrap(context.Canceled, fail.WithClassification()))
+	assert.Equal(t, fail.CodeCanceled, err.Code)
+	assert.Empty(t, err.Tags)
+
+	m := fail.MustParseMatcher("root:sql.ErrNoRows")
+	assert.True(t, m(fail.Wrap(sql.ErrNoRows)))
+	assert.False(t, m(fail.Wrap(sql.ErrTxDone)))
+}
@@ -0,0 +1,53 @@
+package failsql
+
+import "github.com/srvc/fail/v4"
+
+// ParamMySQLErrorNumber is the key of the param of the error number of MySQL errors
+const ParamMySQLErrorNumber = "mysql_error_number"
+
+// MySQLClassifier returns a classifier of MySQL errors with the function that extracts the error number.
+// It's not registered by default to avoid depending on a driver.
+//
+//	failsql.RegisterClassifier(failsql.MySQLClassifier(func(err error) (uint16, bool) {
+//		var me *mysql.MySQLError
+//		if errors.As(err, &me) {
+//			return me.Number, true
+//		}
+//		return 0, false
+//	}))
+func MySQLClassifier(number func(err error) (uint16, bool)) Classifier {
+	return func(err error) fail.Annotator {
+		n, ok := number(err)
+		if !ok {
+			return nil
+		}
+
+		f := classifyMySQLErrorNumber(n)
+		return func(err *fail.Error) {
+			f(err)
+			fail.WithParam(ParamMySQLErrorNumber, int(n))(err)
+		}
+	}
+}
+
+// classifyMySQLErrorNumber classifies an error number of MySQL
+func classifyMySQLErrorNumber(n uint16) fail.Annotator {
+	switch n {
+	case 1062, 1586:
+		// ER_DUP_ENTRY, ER_DUP_ENTRY_WITH_KEY_NAME
+		return annotate(fail.CodeAlreadyExists, false, TagUniqueViolation)
+	case 1216, 1217, 1451, 1452:
+		// ER_NO_REFERENCED_ROW, ER_ROW_IS_REFERENCED, and their second versions
+		return annotate(fail.CodeFailedPrecondition, false, TagForeignKeyViolation)
+	case 1213:
+		// ER_LOCK_DEADLOCK
+		return annotate(fail.CodeAborted, true, TagDeadlock)
+	case 1205:
+		// ER_LOCK_WAIT_TIMEOUT
+		return annotate(fail.CodeAborted, true)
+	case 1040, 1053, 2002, 2003, 2006, 2013:
+		// ER_CON_COUNT_ERROR, ER_SERVER_SHUTDOWN, and client errors of lost connections
+		return annotate(fail.CodeUnavailable, true)
+	}
+	return annotate(fail.CodeInternal, false)
+}
@@ -0,0 +1,69 @@
+package failsql
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/srvc/fail/v4"
+)
+
+// ParamSQLState is the key of the param of the SQLSTATE of Postgres errors
+const ParamSQLState = "sqlstate"
+
+// Tags of the classified Postgres and MySQL errors
+const (
+	TagUniqueViolation      = "unique_violation"
+	TagForeignKeyViolation  = "foreign_key_violation"
+	TagSerializationFailure = "serialization_failure"
+	TagDeadlock             = "deadlock"
+)
+
+// sqlStater is implemented by errors of Postgres drivers, such as *pgconn.PgError of pgx and *pq.Error of lib/pq
+type sqlStater interface {
+	SQLState() string
+}
+
+// ClassifyPostgres classifies errors of Postgres drivers that have the SQLState method.
+// It's registered by default.
+func ClassifyPostgres(err error) fail.Annotator {
+	var s sqlStater
+	if !errors.As(err, &s) {
+		return nil
+	}
+	state := s.SQLState()
+
+	f := classifySQLState(state)
+	return func(err *fail.Error) {
+		f(err)
+		fail.WithParam(ParamSQLState, state)(err)
+	}
+}
+
+// classifySQLState classifies an SQLSTATE code
+func classifySQLState(state string) fail.Annotator {
+	switch state {
+	case "23505":
+		return annotate(fail.CodeAlreadyExists, false, TagUniqueViolation)
+	case "23503":
+		return annotate(fail.CodeFailedPrecondition, false, TagForeignKeyViolation)
+	case "40001":
+		return annotate(fail.CodeAborted, true, TagSerializationFailure)
+	case "40P01":
+		return annotate(fail.CodeAborted, true, TagDeadlock)
+	case "57014":
+		return annotate(fail.CodeCanceled, false)
+	}
+
+	switch {
+	case strings.HasPrefix(state, "08"), strings.HasPrefix(state, "57P"):
+		// connection exception, or operator intervention such as shutdown
+		return annotate(fail.CodeUnavailable, true)
+	case strings.HasPrefix(state, "22"), strings.HasPrefix(state, "23"):
+		// data exception, or other integrity constraint violations
+		return annotate(fail.CodeInvalidArgument, false)
+	case strings.HasPrefix(state, "53"):
+		// insufficient resources
+		return annotate(fail.CodeResourceExhausted, true)
+	}
+	return annotate(fail.CodeInternal, false)
+}
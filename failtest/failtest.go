@@ -0,0 +1,198 @@
+// Package failtest provides assertions on errors of fail for tests.
+//
+// Every assertion unwraps the error with fail.Unwrap, reports a readable failure
+// to t if the assertion doesn't hold, and returns whether it holds.
+package failtest
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/srvc/fail/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+var update = flag.Bool("failtest.update", false, "update golden files of failtest")
+
+type tHelper interface {
+	Helper()
+}
+
+// unwrap extracts a *fail.Error from the error, or reports a failure if it can't
+func unwrap(t assert.TestingT, err error, msgAndArgs ...interface{}) (*fail.Error, bool) {
+	if h, ok := t.(tHelper); ok {
+		h.Helper()
+	}
+
+	if err == nil {
+		return nil, assert.Fail(t, "Expected an error, but got nil", msgAndArgs...)
+	}
+	failErr := fail.Unwrap(err)
+	if failErr == nil {
+		return nil, assert.Fail(t, fmt.Sprintf("Expected a *fail.Error, but got %T: %v", err, err), msgAndArgs...)
+	}
+	return failErr, true
+}
+
+// messageOr returns msgAndArgs, or the formatted default message if msgAndArgs is empty
+func messageOr(msgAndArgs []interface{}, format string, args ...interface{}) []interface{} {
+	if len(msgAndArgs) > 0 {
+		return msgAndArgs
+	}
+	return []interface{}{fmt.Sprintf(format, args...)}
+}
+
+// AssertCode asserts that the error has the code
+func AssertCode(t assert.TestingT, err error, want interface{}, msgAndArgs ...interface{}) bool {
+	if h, ok := t.(tHelper); ok {
+		h.Helper()
+	}
+
+	failErr, ok := unwrap(t, err, msgAndArgs...)
+	if !ok {
+		return false
+	}
+	return assert.Equal(t, want, failErr.Code, messageOr(msgAndArgs, "code of %v", err)...)
+}
+
+// AssertTag asserts that the error has the tag
+func AssertTag(t assert.TestingT, err error, tag string, msgAndArgs ...interface{}) bool {
+	if h, ok := t.(tHelper); ok {
+		h.Helper()
+	}
+
+	failErr, ok := unwrap(t, err, msgAndArgs...)
+	if !ok {
+		return false
+	}
+	for _, tg := range failErr.Tags {
+		if tg == tag {
+			return true
+		}
+	}
+	return assert.Fail(t, fmt.Sprintf("Expected tag %q, but the error has tags %q", tag, failErr.Tags), msgAndArgs...)
+}
+
+// AssertParam asserts that the error has the param of the key and the value.
+// The key can be a dotted path of nested params.
+func AssertParam(t assert.TestingT, err error, key string, want interface{}, msgAndArgs ...interface{}) bool {
+	if h, ok := t.(tHelper); ok {
+		h.Helper()
+	}
+
+	failErr, ok := unwrap(t, err, msgAndArgs...)
+	if !ok {
+		return false
+	}
+	got, ok := failErr.Params.Lookup(key)
+	if !ok {
+		return assert.Fail(t, fmt.Sprintf("Expected param %q, but the error has params %v", key, failErr.Params), msgAndArgs...)
+	}
+	return assert.Equal(t, want, got, messageOr(msgAndArgs, "param %q of %v", key, err)...)
+}
+
+// AssertMessages asserts that the error has the messages from outermost to innermost
+func AssertMessages(t assert.TestingT, err error, want []string, msgAndArgs ...interface{}) bool {
+	if h, ok := t.(tHelper); ok {
+		h.Helper()
+	}
+
+	failErr, ok := unwrap(t, err, msgAndArgs...)
+	if !ok {
+		return false
+	}
+	if len(want) == 0 && len(failErr.Messages) == 0 {
+		return true
+	}
+	return assert.Equal(t, want, failErr.Messages, messageOr(msgAndArgs, "messages of %v", err)...)
+}
+
+// AssertRootCause asserts that the root cause of the error is, or wraps, the target
+func AssertRootCause(t assert.TestingT, err error, target error, msgAndArgs ...interface{}) bool {
+	if h, ok := t.(tHelper); ok {
+		h.Helper()
+	}
+
+	failErr, ok := unwrap(t, err, msgAndArgs...)
+	if !ok {
+		return false
+	}
+	if failErr.Err == target || errors.Is(failErr.Err, target) {
+		return true
+	}
+	return assert.Fail(t, fmt.Sprintf("Expected the root cause to be %#v\n\tbut got %#v", target, failErr.Err), msgAndArgs...)
+}
+
+// AssertStackContains asserts that the stack trace of the error has a frame of the function.
+// The function name is the one in fail.Frame, such as "FindUser" or "(*Repo).FindUser".
+func AssertStackContains(t assert.TestingT, err error, funcName string, msgAndArgs ...interface{}) bool {
+	if h, ok := t.(tHelper); ok {
+		h.Helper()
+	}
+
+	failErr, ok := unwrap(t, err, msgAndArgs...)
+	if !ok {
+		return false
+	}
+	funcs := make([]string, len(failErr.StackTrace))
+	for i, f := range failErr.StackTrace {
+		if f.Func == funcName {
+			return true
+		}
+		funcs[i] = f.Func
+	}
+	return assert.Fail(t, fmt.Sprintf("Expected a frame of %q in the stack trace:\n\t%s", funcName, strings.Join(funcs, "\n\t")), msgAndArgs...)
+}
+
+var (
+	frameFileRegexp = regexp.MustCompile(`(?m)^\t(?:.*/)?([^/\n]+):\d+$`)
+	timeRegexp      = regexp.MustCompile(`(?m)^(    (?:created_at|wrapped_at): ).*$`)
+	goroutineRegexp = regexp.MustCompile(`(?m)^(    goroutine: )\d+$`)
+)
+
+// Normalize returns the "%+v" output of the error
+// with directories of files and line numbers removed from the stack trace,
+// and with timestamps and the goroutine ID replaced with "T" and "N",
+// so that it doesn't depend on the environment or the exact position of code.
+func Normalize(err error) string {
+	s := frameFileRegexp.ReplaceAllString(fmt.Sprintf("%+v", err), "\t$1:N")
+	s = timeRegexp.ReplaceAllString(s, "${1}T")
+	return goroutineRegexp.ReplaceAllString(s, "${1}N")
+}
+
+// AssertGolden asserts that the normalized "%+v" output of the error
+// equals to the content of testdata/<name>.golden.
+// The golden file is written instead if the test is run with -failtest.update.
+func AssertGolden(t assert.TestingT, err error, name string, msgAndArgs ...interface{}) bool {
+	if h, ok := t.(tHelper); ok {
+		h.Helper()
+	}
+
+	failErr, ok := unwrap(t, err, msgAndArgs...)
+	if !ok {
+		return false
+	}
+	got := Normalize(failErr) + "\n"
+	path := filepath.Join("testdata", name+".golden")
+
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return assert.Fail(t, fmt.Sprintf("Failed to create a directory for %s: %v", path, err), msgAndArgs...)
+		}
+		if err := ioutil.WriteFile(path, []byte(got), 0644); err != nil {
+			return assert.Fail(t, fmt.Sprintf("Failed to write %s: %v", path, err), msgAndArgs...)
+		}
+	}
+
+	want, err := ioutil.ReadFile(path)
+	if err != nil {
+		return assert.Fail(t, fmt.Sprintf("Failed to read %s: %v (run with -failtest.update to create it)", path, err), msgAndArgs...)
+	}
+	return assert.Equal(t, string(want), got, messageOr(msgAndArgs, "golden file %s", path)...)
+}
@@ -0,0 +1,178 @@
+package failtest
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/srvc/fail/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockT records failures reported by assertions
+type mockT struct {
+	failures []string
+}
+
+func (t *mockT) Errorf(format string, args ...interface{}) {
+	t.failures = append(t.failures, fmt.Sprintf(format, args...))
+}
+
+var errOrigin = errors.New("origin")
+
+func newTestError() error {
+	return fail.Wrap(
+		errOrigin,
+		fail.WithMessage("message 1"),
+		fail.WithCode(404),
+		fail.WithTags("http", "user"),
+		fail.WithParam("user_id", 1),
+	)
+}
+
+func TestAssertions(t *testing.T) {
+	err := fail.Wrap(newTestError(), fail.WithMessage("message 2"))
+
+	cases := []struct {
+		test   string
+		assert func(t assert.TestingT) bool
+		ok     bool
+		msg    string
+	}{
+		{
+			test:   "AssertCode",
+			assert: func(t assert.TestingT) bool { return AssertCode(t, err, 404) },
+			ok:     true,
+		},
+		{
+			test:   "AssertCode mismatch",
+			assert: func(t assert.TestingT) bool { return AssertCode(t, err, 500) },
+			msg:    "code of message 2: message 1: origin",
+		},
+		{
+			test:   "AssertCode nil",
+			assert: func(t assert.TestingT) bool { return AssertCode(t, nil, 404) },
+			msg:    "Expected an error, but got nil",
+		},
+		{
+			test:   "AssertCode not a fail error",
+			assert: func(t assert.TestingT) bool { return AssertCode(t, errOrigin, 404) },
+			msg:    "Expected a *fail.Error, but got *errors.errorString: origin",
+		},
+		{
+			test:   "AssertTag",
+			assert: func(t assert.TestingT) bool { return AssertTag(t, err, "user") },
+			ok:     true,
+		},
+		{
+			test:   "AssertTag mismatch",
+			assert: func(t assert.TestingT) bool { return AssertTag(t, err, "db") },
+			msg:    `Expected tag "db", but the error has tags ["http" "user"]`,
+		},
+		{
+			test:   "AssertParam",
+			assert: func(t assert.TestingT) bool { return AssertParam(t, err, "user_id", 1) },
+			ok:     true,
+		},
+		{
+			test:   "AssertParam mismatch",
+			assert: func(t assert.TestingT) bool { return AssertParam(t, err, "user_id", 2) },
+			msg:    `param "user_id" of message 2: message 1: origin`,
+		},
+		{
+			test:   "AssertParam missing",
+			assert: func(t assert.TestingT) bool { return AssertParam(t, err, "name", "foo") },
+			msg:    `Expected param "name", but the error has params map[user_id:1]`,
+		},
+		{
+			test:   "AssertMessages",
+			assert: func(t assert.TestingT) bool { return AssertMessages(t, err, []string{"message 2", "message 1"}) },
+			ok:     true,
+		},
+		{
+			test:   "AssertMessages empty",
+			assert: func(t assert.TestingT) bool { return AssertMessages(t, fail.Wrap(errOrigin), nil) },
+			ok:     true,
+		},
+		{
+			test:   "AssertMessages mismatch",
+			assert: func(t assert.TestingT) bool { return AssertMessages(t, err, []string{"message 2"}, "custom %d", 1) },
+			msg:    "custom 1",
+		},
+		{
+			test:   "AssertRootCause",
+			assert: func(t assert.TestingT) bool { return AssertRootCause(t, err, errOrigin) },
+			ok:     true,
+		},
+		{
+			test:   "AssertRootCause mismatch",
+			assert: func(t assert.TestingT) bool { return AssertRootCause(t, err, errors.New("origin")) },
+			msg:    "Expected the root cause to be",
+		},
+		{
+			test:   "AssertStackContains",
+			assert: func(t assert.TestingT) bool { return AssertStackContains(t, err, "newTestError") },
+			ok:     true,
+		},
+		{
+			test:   "AssertStackContains mismatch",
+			assert: func(t assert.TestingT) bool { return AssertStackContains(t, err, "missing") },
+			msg:    `Expected a frame of "missing" in the stack trace:`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.test, func(t *testing.T) {
+			mt := &mockT{}
+			ok := c.assert(mt)
+			assert.Equal(t, c.ok, ok)
+			if c.ok {
+				assert.Empty(t, mt.failures)
+			} else {
+				assert.Len(t, mt.failures, 1)
+				assert.Contains(t, mt.failures[0], c.msg)
+			}
+		})
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	err := &fail.Error{
+		Err:         errOrigin,
+		Code:        500,
+		GoroutineID: 18,
+		CreatedAt:   time.Now(),
+		WrappedAt:   time.Now(),
+		StackTrace: fail.StackTrace{
+			{Func: "f1", File: "github.com/example/app/main.go", Line: 157},
+			{Func: "main", File: "/home/user/app/main.go", Line: 179},
+			{Func: "init", File: "main.go", Line: 10},
+		},
+	}
+
+	assert.Equal(t, `origin
+    code: 500
+    goroutine: N
+    created_at: T
+    wrapped_at: T
+f1
+	main.go:N
+main
+	main.go:N
+init
+	main.go:N`, Normalize(err))
+}
+
+func TestAssertGolden(t *testing.T) {
+	AssertGolden(t, fail.Wrap(newTestError(), fail.WithMessage("message 2")), "error")
+
+	defer func(u bool) { *update = u }(*update)
+	*update = false
+
+	mt := &mockT{}
+	assert.False(t, AssertGolden(mt, newTestError(), "error"))
+	assert.False(t, AssertGolden(mt, newTestError(), "missing"))
+	assert.Len(t, mt.failures, 2)
+	assert.Contains(t, mt.failures[1], "run with -failtest.update to create it")
+}
@@ -0,0 +1,76 @@
+// Package failvalidator converts errors of github.com/go-playground/validator into errors of fail.
+package failvalidator
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/srvc/fail/v4"
+)
+
+// MessageFunc returns the message of a violation of the field
+type MessageFunc func(fe validator.FieldError) string
+
+// Option configures Violations
+type Option func(*config)
+
+type config struct {
+	message MessageFunc
+}
+
+// WithMessageFunc sets the function that returns the message of each violation.
+// It's the message of validator.FieldError without the namespace by default, such as "Field validation for 'name' failed on the 'required' tag".
+func WithMessageFunc(f MessageFunc) Option {
+	return func(c *config) {
+		c.message = f
+	}
+}
+
+// Violations converts validator.ValidationErrors into the violations of fail.Validation.
+// The field path is the namespace of the field without the name of the top-level struct,
+// so it follows the names registered with validator.Validate.RegisterTagNameFunc.
+func Violations(errs validator.ValidationErrors, opts ...Option) *fail.Validation {
+	cfg := &config{message: defaultMessage}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	v := fail.NewValidation()
+	for _, fe := range errs {
+		v.Add(fieldPath(fe), fe.Tag(), cfg.message(fe))
+	}
+	return v
+}
+
+// Wrap converts validator.ValidationErrors into an error built by fail.Validation
+// with fail.CodeInvalidArgument and the default messages, and applies the annotators.
+// Other errors are wrapped with fail.Wrap.
+// It returns nil if err is nil.
+func Wrap(err error, annotators ...fail.Annotator) error {
+	var verrs validator.ValidationErrors
+	if !errors.As(err, &verrs) {
+		return fail.WrapSkip(err, 1, annotators...)
+	}
+
+	verr := &fail.ValidationError{Violations: Violations(verrs).Violations()}
+	return fail.WrapSkip(verr, 1, append([]fail.Annotator{fail.WithCode(fail.CodeInvalidArgument)}, annotators...)...)
+}
+
+// fieldPath returns the namespace of the field without the name of the top-level struct
+func fieldPath(fe validator.FieldError) string {
+	ns := fe.Namespace()
+	if i := strings.Index(ns, "."); i >= 0 {
+		return ns[i+1:]
+	}
+	return ns
+}
+
+// defaultMessage returns the message of the field error without the namespace
+func defaultMessage(fe validator.FieldError) string {
+	msg := fe.Error()
+	if i := strings.Index(msg, "Error:"); i >= 0 {
+		return msg[i+len("Error:"):]
+	}
+	return msg
+}
@@ -0,0 +1,74 @@
+package failvalidator
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/srvc/fail/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+type address struct {
+	Zip string `json:"zip" validate:"len=7"`
+}
+
+type user struct {
+	Name      string    `json:"name" validate:"required"`
+	Age       int       `json:"age" validate:"min=0"`
+	Addresses []address `json:"addresses" validate:"dive"`
+}
+
+func newValidate() *validator.Validate {
+	validate := validator.New()
+	validate.RegisterTagNameFunc(func(f reflect.StructField) string {
+		return strings.Split(f.Tag.Get("json"), ",")[0]
+	})
+	return validate
+}
+
+var invalidUser = user{Age: -1, Addresses: []address{{Zip: "1234567"}, {Zip: "123"}}}
+
+func TestWrap(t *testing.T) {
+	err := Wrap(newValidate().Struct(invalidUser), fail.WithMessage("invalid user"))
+
+	assert.Equal(t, []fail.FieldViolation{
+		{Field: "name", Constraint: "required", Message: "Field validation for 'name' failed on the 'required' tag"},
+		{Field: "age", Constraint: "min", Message: "Field validation for 'age' failed on the 'min' tag"},
+		{Field: "addresses[1].zip", Constraint: "len", Message: "Field validation for 'zip' failed on the 'len' tag"},
+	}, fail.FieldViolations(err))
+
+	failErr := fail.Unwrap(err)
+	assert.Equal(t, fail.CodeInvalidArgument, failErr.Code)
+	assert.Equal(t, []string{"invalid user"}, failErr.Messages)
+	assert.Equal(t, "TestWrap", failErr.StackTrace[0].Func)
+
+	t.Run("other error", func(t *testing.T) {
+		err := Wrap(errors.New("origin"), fail.WithCode(500))
+		assert.Equal(t, 500, fail.Unwrap(err).Code)
+		assert.Equal(t, "TestWrap.func1", fail.Unwrap(err).StackTrace[0].Func)
+		assert.Nil(t, fail.FieldViolations(err))
+	})
+
+	t.Run("nil", func(t *testing.T) {
+		assert.NoError(t, Wrap(nil))
+		assert.NoError(t, Wrap(newValidate().Struct(user{Name: "foo"})))
+	})
+}
+
+func TestViolations(t *testing.T) {
+	var verrs validator.ValidationErrors
+	assert.True(t, errors.As(newValidate().Struct(invalidUser), &verrs))
+
+	v := Violations(verrs, WithMessageFunc(func(fe validator.FieldError) string {
+		return fe.Tag() + " " + fe.Param()
+	}))
+
+	assert.Equal(t, []fail.FieldViolation{
+		{Field: "name", Constraint: "required", Message: "required "},
+		{Field: "age", Constraint: "min", Message: "min 0"},
+		{Field: "addresses[1].zip", Constraint: "len", Message: "len 7"},
+	}, v.Violations())
+}
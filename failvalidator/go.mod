@@ -0,0 +1,23 @@
+module github.com/srvc/fail/v4/failvalidator
+
+go 1.23.0
+
+require (
+	github.com/go-playground/validator/v10 v10.27.0
+	github.com/srvc/fail/v4 v4.0.0
+	github.com/stretchr/testify v1.12.1
+)
+
+require (
+	github.com/gabriel-vasile/mimetype v1.4.15 // indirect
+	github.com/go-playground/locales v0.14.1 // indirect
+	github.com/go-playground/universal-translator v0.18.1 // indirect
+	github.com/leodido/go-urn v1.4.0 // indirect
+	github.com/pkg/errors v0.8.1 // indirect
+	go.yaml.in/yaml/v3 v3.0.5 // indirect
+	golang.org/x/crypto v0.41.0 // indirect
+	golang.org/x/sys v0.35.0 // indirect
+	golang.org/x/text v0.28.0 // indirect
+)
+
+replace github.com/srvc/fail/v4 => ../
@@ -0,0 +1,34 @@
+package fail
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"io"
+)
+
+// Fingerprint returns a hash that groups errors of the same cause.
+// It is computed from the type of the root error, the kind, and the functions and files
+// of the stack trace, so it is stable across changes of messages, params and line numbers.
+// The root error message is used instead if the error has no stack trace.
+func (e *Error) Fingerprint() string {
+	h := sha1.New()
+
+	io.WriteString(h, RootTypeName(e.Err))
+	io.WriteString(h, "\x00")
+	if e.Kind != nil {
+		io.WriteString(h, e.Kind.ID())
+	}
+	io.WriteString(h, "\x00")
+
+	if len(e.StackTrace) == 0 {
+		io.WriteString(h, e.Err.Error())
+	}
+	for _, f := range e.StackTrace {
+		io.WriteString(h, f.Func)
+		io.WriteString(h, "\x00")
+		io.WriteString(h, f.File)
+		io.WriteString(h, "\x00")
+	}
+
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
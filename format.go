@@ -0,0 +1,144 @@
+package fail
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Format implements fmt.Formatter.
+//
+//	%s, %v  the error message
+//	%q      the quoted error message
+//	%x, %X  the error message in hex
+//	%+v     the error message followed by the metadata and the stack trace
+//	%+#v    %+v with source code around each frame loaded by the SourceLoader set by SetSourceLoader
+//
+// The metadata are indented by 4 spaces, and each frame of the stack trace
+// is printed as a function name and a tab-indented "file:line".
+// Frames of other services are preceded by a separator such as "--- remote (users) ---".
+// The width and the flags apply to the message as they do to strings.
+// Other verbs, including %#v, print the struct as fmt does by default.
+func (e *Error) Format(s fmt.State, verb rune) {
+	switch {
+	case verb == 'v' && s.Flag('+'):
+		io.WriteString(s, e.Error())
+		e.formatMetadata(s)
+		var loader *SourceLoader
+		if s.Flag('#') {
+			loader = getSourceLoader()
+		}
+		e.StackTrace.format(s, loader)
+	case verb == 'v' && !s.Flag('#'), verb == 's', verb == 'q', verb == 'x', verb == 'X':
+		fmt.Fprintf(s, formatDirective(s, verb), e.Error())
+	default:
+		e.formatDefault(s, verb)
+	}
+}
+
+// formatDefault writes the error as fmt does for a struct without the Format method
+func (e *Error) formatDefault(s fmt.State, verb rune) {
+	type plainError Error
+	str := fmt.Sprintf(formatDirective(s, verb), (*plainError)(e))
+	if verb == 'v' {
+		str = strings.Replace(str, "fail.plainError{", "fail.Error{", 1)
+	}
+	io.WriteString(s, str)
+}
+
+// formatDirective rebuilds the directive, such as "%#v", from the state
+func formatDirective(s fmt.State, verb rune) string {
+	var b strings.Builder
+	b.WriteByte('%')
+	for _, flag := range "+-# 0" {
+		if s.Flag(int(flag)) {
+			b.WriteRune(flag)
+		}
+	}
+	if width, ok := s.Width(); ok {
+		fmt.Fprintf(&b, "%d", width)
+	}
+	if prec, ok := s.Precision(); ok {
+		fmt.Fprintf(&b, ".%d", prec)
+	}
+	b.WriteRune(verb)
+	return b.String()
+}
+
+// formatMetadata writes the metadata of the error line by line
+func (e *Error) formatMetadata(w io.Writer) {
+	if e.Kind != nil {
+		fmt.Fprintf(w, "\n    kind: %s", e.Kind.ID())
+	}
+	if e.Code != nil {
+		fmt.Fprintf(w, "\n    code: %v", e.Code)
+	}
+	if e.Ignorable {
+		io.WriteString(w, "\n    ignorable: true")
+	}
+	if e.Severity != SeverityUnspecified {
+		fmt.Fprintf(w, "\n    severity: %s", e.Severity)
+	}
+	if e.Retryable {
+		io.WriteString(w, "\n    retryable: true")
+	}
+	if e.RetryAfter != 0 {
+		fmt.Fprintf(w, "\n    retry_after: %s", e.RetryAfter)
+	}
+	if len(e.Tags) > 0 {
+		fmt.Fprintf(w, "\n    tags: %s", strings.Join(e.Tags, ", "))
+	}
+	if len(e.Params) > 0 {
+		fmt.Fprintf(w, "\n    params: %s", e.Params.format())
+	}
+	if len(e.Labels) > 0 {
+		labels := make(H, len(e.Labels))
+		for k, v := range e.Labels {
+			labels[k] = v
+		}
+		fmt.Fprintf(w, "\n    labels: %s", labels.format())
+	}
+	if e.GoroutineID != 0 {
+		fmt.Fprintf(w, "\n    goroutine: %d", e.GoroutineID)
+	}
+	if !e.CreatedAt.IsZero() {
+		fmt.Fprintf(w, "\n    created_at: %s", e.CreatedAt.Format(time.RFC3339Nano))
+	}
+	if !e.WrappedAt.IsZero() {
+		fmt.Fprintf(w, "\n    wrapped_at: %s", e.WrappedAt.Format(time.RFC3339Nano))
+	}
+}
+
+// format writes the frames of the stack trace line by line.
+// Source code around each local frame is written with line numbers if the loader is not nil.
+func (st StackTrace) format(w io.Writer, loader *SourceLoader) {
+	for i, f := range st {
+		if sep := st.Separator(i); sep != "" {
+			fmt.Fprintf(w, "\n%s", sep)
+		}
+		fmt.Fprintf(w, "\n%s\n\t%s:%d", f.Func, f.File, f.Line)
+		if loader == nil || f.Remote {
+			continue
+		}
+		if src, ok := loader.Load(f); ok {
+			for _, line := range src.Lines() {
+				marker := " "
+				if line.Current {
+					marker = ">"
+				}
+				fmt.Fprintf(w, "\n\t%s %5d | %s", marker, line.Number, line.Text)
+			}
+		}
+	}
+}
+
+// format formats the params as "path=value" pairs sorted by dotted paths
+func (h H) format() string {
+	params := h.Flatten()
+	pairs := make([]string, len(params))
+	for i, p := range params {
+		pairs[i] = fmt.Sprintf("%s=%v", p.Path, p.Value)
+	}
+	return strings.Join(pairs, ", ")
+}
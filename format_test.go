@@ -0,0 +1,125 @@
+package fail
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestError_Format(t *testing.T) {
+	err := &Error{
+		Err:       errors.New("origin"),
+		Messages:  []string{"message 2", "message 1"},
+		Code:      500,
+		Ignorable: true,
+		Tags:      []string{"http", "security"},
+		Params:    H{"foo": 1, "bar": "baz"},
+		StackTrace: StackTrace{
+			{Func: "f1", File: "main.go", Line: 157},
+			{Func: "main", File: "main.go", Line: 179},
+		},
+		Kind: Define("internal"),
+	}
+
+	t.Run("%s", func(t *testing.T) {
+		assert.Equal(t, "message 2: message 1: origin", fmt.Sprintf("%s", err))
+	})
+
+	t.Run("%v", func(t *testing.T) {
+		assert.Equal(t, "message 2: message 1: origin", fmt.Sprintf("%v", err))
+	})
+
+	t.Run("%q", func(t *testing.T) {
+		assert.Equal(t, `"message 2: message 1: origin"`, fmt.Sprintf("%q", err))
+	})
+
+	t.Run("%#v", func(t *testing.T) {
+		err := &Error{Err: errors.New("origin"), Code: 500}
+		got := fmt.Sprintf("%#v", err)
+		assert.True(t, strings.HasPrefix(got, `&fail.Error{Err:(*errors.errorString)`), got)
+		assert.Contains(t, got, `Code:500`)
+		assert.NotContains(t, got, "plainError")
+	})
+
+	t.Run("width and flags", func(t *testing.T) {
+		err := &Error{Err: errors.New("boom")}
+		assert.Equal(t, "      boom", fmt.Sprintf("%10s", err))
+		assert.Equal(t, "boom    |", fmt.Sprintf("%-8v|", err))
+		assert.Equal(t, `"bo"`, fmt.Sprintf("%.2q", err))
+		assert.Equal(t, "626f6f6d", fmt.Sprintf("%x", err))
+		assert.Equal(t, "626F6F6D", fmt.Sprintf("%X", err))
+		assert.Equal(t, "62 6f 6f 6d", fmt.Sprintf("% x", err))
+		assert.Equal(t, fmt.Sprintf("%10s|%x", errors.New("boom"), errors.New("boom")), fmt.Sprintf("%10s|%x", err, err))
+	})
+
+	t.Run("other verbs", func(t *testing.T) {
+		err := &Error{Err: errors.New("origin"), Code: 500}
+		assert.Equal(t, "*fail.Error", fmt.Sprintf("%T", err))
+		got := fmt.Sprintf("%d", err)
+		assert.True(t, strings.HasPrefix(got, "&{"), got)
+		assert.Contains(t, got, "500")
+	})
+
+	t.Run("%+v", func(t *testing.T) {
+		assert.Equal(t, `message 2: message 1: origin
+    kind: internal
+    code: 500
+    ignorable: true
+    tags: http, security
+    params: bar=baz, foo=1
+f1
+	main.go:157
+main
+	main.go:179`, fmt.Sprintf("%+v", err))
+	})
+
+	t.Run("%+v with occurrence", func(t *testing.T) {
+		err := &Error{
+			Err:         errors.New("origin"),
+			Labels:      map[string]string{"method": "GET", "handler": "users"},
+			GoroutineID: 18,
+			CreatedAt:   time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+			WrappedAt:   time.Date(2020, 1, 1, 0, 0, 1, 500, time.UTC),
+		}
+		assert.Equal(t, `origin
+    labels: handler=users, method=GET
+    goroutine: 18
+    created_at: 2020-01-01T00:00:00Z
+    wrapped_at: 2020-01-01T00:00:01.0000005Z`, fmt.Sprintf("%+v", err))
+	})
+
+	t.Run("%+v with remote frames", func(t *testing.T) {
+		err := &Error{
+			Err: errors.New("origin"),
+			StackTrace: StackTrace{
+				{Func: "find", File: "users.go", Line: 10, Remote: true, Origin: Origin{Service: "users"}},
+				{Func: "f1", File: "main.go", Line: 157},
+			},
+		}
+		assert.Equal(t, `origin
+--- remote (users) ---
+find
+	users.go:10
+--- local ---
+f1
+	main.go:157`, fmt.Sprintf("%+v", err))
+	})
+
+	t.Run("%+v with nested params", func(t *testing.T) {
+		err := &Error{
+			Err:    errors.New("origin"),
+			Params: H{"user": H{"name": "foo", "id": 1}, "method": "GET"},
+		}
+		assert.Equal(t, `origin
+    params: method=GET, user.id=1, user.name=foo`, fmt.Sprintf("%+v", err))
+	})
+
+	t.Run("%+v without metadata", func(t *testing.T) {
+		err := &Error{Err: errors.New("origin")}
+		assert.Equal(t, "origin", fmt.Sprintf("%+v", err))
+	})
+}
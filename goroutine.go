@@ -0,0 +1,54 @@
+package fail
+
+import (
+	"bytes"
+	"context"
+	"runtime"
+	"runtime/pprof"
+	"strconv"
+)
+
+// WithGoroutineID annotates an error with the ID of the current goroutine.
+// Register it with OnCreate to record the goroutine where every error is created.
+func WithGoroutineID() Annotator {
+	return func(err *Error) {
+		err.GoroutineID = goroutineID()
+	}
+}
+
+// WithLabels annotates an error with the profiler labels of the context set by pprof.WithLabels.
+// The labels are added to a copy of the existing ones, since Copy shares them with the wrapped error.
+func WithLabels(ctx context.Context) Annotator {
+	return func(err *Error) {
+		if ctx == nil {
+			return
+		}
+		var labels map[string]string
+		pprof.ForLabels(ctx, func(key, value string) bool {
+			if labels == nil {
+				labels = make(map[string]string, len(err.Labels)+1)
+				for k, v := range err.Labels {
+					labels[k] = v
+				}
+			}
+			labels[key] = value
+			return true
+		})
+		if labels != nil {
+			err.Labels = labels
+		}
+	}
+}
+
+// goroutineID returns the ID of the current goroutine parsed from the header of its stack,
+// such as "goroutine 18 [running]:". It returns 0 if it fails to parse.
+func goroutineID() int64 {
+	buf := make([]byte, 64)
+	buf = buf[:runtime.Stack(buf, false)]
+	buf = bytes.TrimPrefix(buf, []byte("goroutine "))
+	if i := bytes.IndexByte(buf, ' '); i >= 0 {
+		buf = buf[:i]
+	}
+	id, _ := strconv.ParseInt(string(buf), 10, 64)
+	return id
+}
@@ -0,0 +1,45 @@
+package fail
+
+import (
+	"context"
+	"runtime/pprof"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithGoroutineID(t *testing.T) {
+	err := Wrap(New("origin"), WithGoroutineID())
+	id := Unwrap(err).GoroutineID
+	assert.NotZero(t, id)
+
+	ch := make(chan int64)
+	go func() {
+		ch <- Unwrap(Wrap(New("origin"), WithGoroutineID())).GoroutineID
+	}()
+	other := <-ch
+	assert.NotZero(t, other)
+	assert.NotEqual(t, id, other)
+}
+
+func TestWithLabels(t *testing.T) {
+	ctx := pprof.WithLabels(context.Background(), pprof.Labels("handler", "users", "method", "GET"))
+
+	err := Wrap(New("origin"), WithLabels(ctx))
+	assert.Equal(t, map[string]string{"handler": "users", "method": "GET"}, Unwrap(err).Labels)
+
+	t.Run("wrapped", func(t *testing.T) {
+		ctx1 := pprof.WithLabels(context.Background(), pprof.Labels("a", "1"))
+		ctx2 := pprof.WithLabels(context.Background(), pprof.Labels("b", "2"))
+
+		e1 := Unwrap(Wrap(New("origin"), WithLabels(ctx1)))
+		e2 := Unwrap(Wrap(e1, WithLabels(ctx2)))
+		assert.Equal(t, map[string]string{"a": "1"}, e1.Labels)
+		assert.Equal(t, map[string]string{"a": "1", "b": "2"}, e2.Labels)
+	})
+
+	t.Run("without labels", func(t *testing.T) {
+		err := Wrap(New("origin"), WithLabels(context.Background()))
+		assert.Nil(t, Unwrap(err).Labels)
+	})
+}
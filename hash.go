@@ -1,5 +1,15 @@
 package fail
 
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// pathSeparator separates keys of nested params in a path
+const pathSeparator = "."
+
 // H represents a JSON-like key-value object.
 type H map[string]interface{}
 
@@ -16,3 +26,196 @@ func (h H) Merge(other map[string]interface{}) H {
 
 	return out
 }
+
+// Clone returns a deep copy of the object.
+// Nested objects and []interface{} are copied, and other values are shared.
+func (h H) Clone() H {
+	if h == nil {
+		return nil
+	}
+	return cloneValue(h).(H)
+}
+
+// cloneValue returns a copy of the value if it's an object or []interface{}
+func cloneValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case H:
+		out := make(H, len(v))
+		for k, e := range v {
+			out[k] = cloneValue(e)
+		}
+		return out
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for k, e := range v {
+			out[k] = cloneValue(e)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, e := range v {
+			out[i] = cloneValue(e)
+		}
+		return out
+	}
+	return v
+}
+
+// MergeStrategy determines how values of the same key are merged
+// when an error is annotated with params more than once by WithParamsStrategy
+type MergeStrategy int
+
+// Merge strategies
+const (
+	// MergeOuterWins replaces the inner value with the outer one without merging nested objects, as Merge and WithParams.
+	MergeOuterWins MergeStrategy = iota
+	// MergeInnerWins keeps the inner value
+	MergeInnerWins
+	// MergeCollect collects the values into []interface{} from inner to outer
+	MergeCollect
+	// MergeNamespaced keeps the inner value, and puts the outer one under "layerN",
+	// where N is the first number that the key is not used under, such as "layer1.user_id" and "layer2.user_id".
+	// N counts the conflicting values of the key, not the depth of wraps.
+	MergeNamespaced
+)
+
+// MergeWith returns a new H object that contains self and other H contents merged with the strategy.
+// Nested objects are merged recursively except by MergeOuterWins, and equal values are not merged by the strategy.
+func (h H) MergeWith(other map[string]interface{}, s MergeStrategy) H {
+	if s == MergeOuterWins {
+		return h.Merge(other)
+	}
+	return h.mergeDeep(other, s)
+}
+
+// mergeDeep merges nested objects recursively, and other values by the strategy
+func (h H) mergeDeep(other map[string]interface{}, s MergeStrategy) H {
+	out := make(H, len(h)+len(other))
+	for k, v := range h {
+		out[k] = v
+	}
+
+	for k, v := range other {
+		cur, ok := out[k]
+		if !ok || reflect.DeepEqual(cur, v) {
+			out[k] = v
+			continue
+		}
+		if cm, ok := asMap(cur); ok {
+			if om, ok := asMap(v); ok {
+				out[k] = H(cm).mergeDeep(om, s)
+				continue
+			}
+		}
+
+		switch s {
+		case MergeInnerWins:
+		case MergeCollect:
+			var list []interface{}
+			if l, ok := cur.([]interface{}); ok {
+				list = make([]interface{}, len(l), len(l)+1)
+				copy(list, l)
+			} else {
+				list = []interface{}{cur}
+			}
+			out[k] = append(list, v)
+		case MergeNamespaced:
+			for n := 1; ; n++ {
+				keys := []string{fmt.Sprintf("layer%d", n), k}
+				if _, ok := out.lookup(keys); !ok {
+					out = out.mergeDeep(nest(keys, v), MergeOuterWins)
+					break
+				}
+			}
+		default:
+			out[k] = v
+		}
+	}
+
+	return out
+}
+
+// Set sets the value at the dotted path such as "user.id", creating nested objects as needed.
+// Values that are not objects on the path are replaced.
+func (h H) Set(path string, value interface{}) {
+	keys := strings.Split(path, pathSeparator)
+	m := map[string]interface{}(h)
+	for _, k := range keys[:len(keys)-1] {
+		next, ok := asMap(m[k])
+		if !ok {
+			h := H{}
+			m[k], next = h, h
+		}
+		m = next
+	}
+	m[keys[len(keys)-1]] = value
+}
+
+// Lookup returns the value at the dotted path such as "user.id".
+// A key that contains dots itself is also found.
+func (h H) Lookup(path string) (interface{}, bool) {
+	if v, ok := h[path]; ok {
+		return v, true
+	}
+	return h.lookup(strings.Split(path, pathSeparator))
+}
+
+// lookup returns the value at the keys of nested objects
+func (h H) lookup(keys []string) (interface{}, bool) {
+	var v interface{} = h
+	for _, k := range keys {
+		m, ok := asMap(v)
+		if !ok {
+			return nil, false
+		}
+		if v, ok = m[k]; !ok {
+			return nil, false
+		}
+	}
+	return v, true
+}
+
+// Param is a value of params at a dotted path
+type Param struct {
+	Path  string
+	Value interface{}
+}
+
+// Flatten returns the values of the params and their nested objects with dotted paths, sorted by the paths.
+// It's the deterministic view of the params used by formatters.
+func (h H) Flatten() []Param {
+	var params []Param
+	h.flatten("", &params)
+	sort.SliceStable(params, func(i, j int) bool { return params[i].Path < params[j].Path })
+	return params
+}
+
+func (h H) flatten(prefix string, params *[]Param) {
+	for k, v := range h {
+		if m, ok := asMap(v); ok && len(m) > 0 {
+			H(m).flatten(prefix+k+pathSeparator, params)
+			continue
+		}
+		*params = append(*params, Param{Path: prefix + k, Value: v})
+	}
+}
+
+// nest returns an object that has the value at the keys of nested objects
+func nest(keys []string, value interface{}) H {
+	h := H{keys[len(keys)-1]: value}
+	for i := len(keys) - 2; i >= 0; i-- {
+		h = H{keys[i]: h}
+	}
+	return h
+}
+
+// asMap returns the value as map[string]interface{} if it's an object
+func asMap(v interface{}) (map[string]interface{}, bool) {
+	switch m := v.(type) {
+	case H:
+		return m, true
+	case map[string]interface{}:
+		return m, true
+	}
+	return nil, false
+}
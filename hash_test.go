@@ -1,6 +1,7 @@
 package fail
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -38,3 +39,152 @@ func TestMergeH(t *testing.T) {
 		})
 	}
 }
+
+func TestH_MergeWith(t *testing.T) {
+	inner := H{"user_id": 1, "name": "foo", "user": H{"id": 1}}
+	outer := H{"user_id": 2, "name": "foo", "user": map[string]interface{}{"id": 2, "role": "admin"}}
+
+	cases := []struct {
+		strategy MergeStrategy
+		want     H
+	}{
+		{
+			strategy: MergeOuterWins,
+			want:     H{"user_id": 2, "name": "foo", "user": map[string]interface{}{"id": 2, "role": "admin"}},
+		},
+		{
+			strategy: MergeInnerWins,
+			want:     H{"user_id": 1, "name": "foo", "user": H{"id": 1, "role": "admin"}},
+		},
+		{
+			strategy: MergeCollect,
+			want:     H{"user_id": []interface{}{1, 2}, "name": "foo", "user": H{"id": []interface{}{1, 2}, "role": "admin"}},
+		},
+		{
+			strategy: MergeNamespaced,
+			want: H{
+				"user_id": 1, "name": "foo", "user": H{"id": 1, "role": "admin", "layer1": H{"id": 2}},
+				"layer1": H{"user_id": 2},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(fmt.Sprint(c.strategy), func(t *testing.T) {
+			assert.Equal(t, c.want, inner.MergeWith(outer, c.strategy))
+			assert.Equal(t, H{"user_id": 1, "name": "foo", "user": H{"id": 1}}, inner, "should not modify the inner params")
+		})
+	}
+
+	t.Run("layers", func(t *testing.T) {
+		h := H{"user_id": 1}
+		h = h.MergeWith(H{"user_id": 2}, MergeNamespaced)
+		h = h.MergeWith(H{"user_id": 3}, MergeNamespaced)
+		assert.Equal(t, H{"user_id": 1, "layer1": H{"user_id": 2}, "layer2": H{"user_id": 3}}, h)
+
+		h = H{"user_id": 1}
+		h = h.MergeWith(H{"user_id": 2}, MergeCollect)
+		h = h.MergeWith(H{"user_id": 3}, MergeCollect)
+		assert.Equal(t, H{"user_id": []interface{}{1, 2, 3}}, h)
+	})
+
+	t.Run("namespaced layers count values", func(t *testing.T) {
+		h := H{"user_id": 1, "name": "foo"}
+		h = h.MergeWith(H{"name": "bar"}, MergeNamespaced)
+		h = h.MergeWith(H{"user_id": 2}, MergeNamespaced)
+		assert.Equal(t, H{"user_id": 1, "name": "foo", "layer1": H{"name": "bar", "user_id": 2}}, h)
+	})
+
+	t.Run("collect copies lists", func(t *testing.T) {
+		list := make([]interface{}, 2, 4)
+		list[0], list[1] = 1, 2
+		h := H{"user_id": list}.MergeWith(H{"user_id": 3}, MergeCollect)
+		assert.Equal(t, []interface{}{1, 2, 3}, h["user_id"])
+		assert.Equal(t, []interface{}{1, 2, nil}, list[:3], "should not write into the list of the caller")
+	})
+}
+
+func TestWithParams_Shallow(t *testing.T) {
+	err := Unwrap(Wrap(
+		Wrap(New("origin"), WithParams(H{"user": H{"id": 1, "role": "admin"}, "user_id": 1})),
+		WithParams(H{"user": H{"id": 2}, "user_id": 2}),
+	))
+	assert.Equal(t, H{"user": H{"id": 2}, "user_id": 2}, err.Params)
+}
+
+func TestWithParamsStrategy(t *testing.T) {
+	err := Unwrap(Wrap(
+		Wrap(New("origin"), WithParamsStrategy(H{"user_id": 1}, MergeCollect)),
+		WithParamsStrategy(H{"user_id": 2}, MergeCollect),
+	))
+	assert.Equal(t, H{"user_id": []interface{}{1, 2}}, err.Params)
+
+	err = Unwrap(Wrap(
+		Wrap(New("origin"), WithParam("user", H{"id": 1, "role": "admin"})),
+		WithParamsStrategy(H{"user": H{"id": 2}}, MergeInnerWins),
+	))
+	assert.Equal(t, H{"user": H{"id": 1, "role": "admin"}}, err.Params)
+}
+
+func TestH_Clone(t *testing.T) {
+	h := H{"user": H{"id": 1}, "raw": map[string]interface{}{"a": 1}, "ids": []interface{}{1, H{"b": 2}}, "name": "foo"}
+	c := h.Clone()
+	assert.Equal(t, h, c)
+
+	h["user"].(H)["id"] = 2
+	h["raw"].(map[string]interface{})["a"] = 2
+	h["ids"].([]interface{})[1].(H)["b"] = 3
+	h["name"] = "bar"
+	assert.Equal(t, H{"user": H{"id": 1}, "raw": map[string]interface{}{"a": 1}, "ids": []interface{}{1, H{"b": 2}}, "name": "foo"}, c)
+
+	assert.Nil(t, H(nil).Clone())
+}
+
+func TestH_Path(t *testing.T) {
+	h := H{"user.id": 0, "role": "admin"}
+	h.Set("user.name", "foo")
+	h.Set("request.header.accept", "*/*")
+	h.Set("role.id", 1)
+	assert.Equal(t, H{
+		"user.id": 0,
+		"user":    H{"name": "foo"},
+		"request": H{"header": H{"accept": "*/*"}},
+		"role":    H{"id": 1},
+	}, h)
+
+	v, ok := h.Lookup("request.header.accept")
+	assert.True(t, ok)
+	assert.Equal(t, "*/*", v)
+
+	v, ok = h.Lookup("user.id")
+	assert.True(t, ok)
+	assert.Equal(t, 0, v)
+
+	_, ok = h.Lookup("request.header.accept.type")
+	assert.False(t, ok)
+	_, ok = h.Lookup("request.body")
+	assert.False(t, ok)
+}
+
+func TestWithParamPath(t *testing.T) {
+	err := Unwrap(Wrap(Wrap(New("origin"), WithParamPath("user.id", 1)), WithParamPath("user.name", "foo")))
+	assert.Equal(t, H{"user": H{"id": 1, "name": "foo"}}, err.Params)
+}
+
+func TestH_Flatten(t *testing.T) {
+	h := H{
+		"user":    H{"name": "foo", "id": 1},
+		"request": map[string]interface{}{"method": "GET", "header": H{}},
+		"b":       []int{1, 2},
+		"a":       nil,
+	}
+	assert.Equal(t, []Param{
+		{Path: "a", Value: nil},
+		{Path: "b", Value: []int{1, 2}},
+		{Path: "request.header", Value: H{}},
+		{Path: "request.method", Value: "GET"},
+		{Path: "user.id", Value: 1},
+		{Path: "user.name", Value: "foo"},
+	}, h.Flatten())
+	assert.Empty(t, H{}.Flatten())
+}
@@ -0,0 +1,52 @@
+package fail
+
+import "sync"
+
+var (
+	hooksMu     sync.RWMutex
+	createHooks []Annotator
+	wrapHooks   []Annotator
+)
+
+// OnCreate registers hooks that are called with every new *Error,
+// that is created by New, Errorf, or Wrap with an error not derived from fail.
+// Hooks are called in the order they were registered,
+// and before the annotators passed to Wrap so that the annotators can override them.
+func OnCreate(hooks ...Annotator) {
+	hooksMu.Lock()
+	defer hooksMu.Unlock()
+	createHooks = append(createHooks, hooks...)
+}
+
+// OnWrap registers hooks that are called with every *Error returned from Wrap.
+// Hooks are called in the order they were registered,
+// and after the annotators passed to Wrap.
+func OnWrap(hooks ...Annotator) {
+	hooksMu.Lock()
+	defer hooksMu.Unlock()
+	wrapHooks = append(wrapHooks, hooks...)
+}
+
+// runHooks calls the hooks with the error
+func runHooks(hooks *[]Annotator, err *Error) {
+	hooksMu.RLock()
+	fs := *hooks
+	hooksMu.RUnlock()
+
+	for _, f := range fs {
+		f(err)
+	}
+}
+
+// isFailError reports whether the error is a *Error
+// or a pkg/errors error which wraps a *Error
+func isFailError(err error) bool {
+	if _, ok := err.(*Error); ok {
+		return true
+	}
+	if pkgErr := extractPkgError(err); pkgErr != nil {
+		_, ok := pkgErr.Err.(*Error)
+		return ok
+	}
+	return false
+}
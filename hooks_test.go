@@ -0,0 +1,138 @@
+package fail
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var errTestNotFound = errors.New("not found")
+
+func TestOnCreate(t *testing.T) {
+	defer restoreHooks()()
+
+	var created []string
+	OnCreate(
+		func(err *Error) {
+			created = append(created, err.Err.Error())
+		},
+		func(err *Error) {
+			if err.Err == errTestNotFound {
+				err.Code = 404
+			}
+		},
+	)
+
+	t.Run("New", func(t *testing.T) {
+		created = nil
+		New("err")
+		assert.Equal(t, []string{"err"}, created)
+	})
+
+	t.Run("Errorf", func(t *testing.T) {
+		created = nil
+		Errorf("err %d", 1)
+		assert.Equal(t, []string{"err 1"}, created)
+	})
+
+	t.Run("Wrap", func(t *testing.T) {
+		created = nil
+		err := Wrap(errTestNotFound)
+		assert.Equal(t, []string{"not found"}, created)
+		assert.Equal(t, 404, Unwrap(err).Code)
+	})
+
+	t.Run("Wrap with annotators", func(t *testing.T) {
+		err := Wrap(errTestNotFound, WithCode(410))
+		assert.Equal(t, 410, Unwrap(err).Code)
+	})
+
+	t.Run("already wrapped", func(t *testing.T) {
+		err := Wrap(errors.New("origin"))
+
+		created = nil
+		Wrap(err)
+		Wrap(pkgErrorsWrap(err, "message"))
+		assert.Empty(t, created)
+	})
+
+	t.Run("with pkg/errors", func(t *testing.T) {
+		created = nil
+		Wrap(pkgErrorsWrap(errors.New("origin"), "message"))
+		assert.Equal(t, []string{"origin"}, created)
+	})
+}
+
+func TestOnWrap(t *testing.T) {
+	defer restoreHooks()()
+
+	var order []string
+	OnWrap(
+		func(err *Error) {
+			order = append(order, "hook 1")
+			err.Params = err.Params.Merge(H{"version": "v1.0.0"})
+		},
+		func(err *Error) {
+			order = append(order, "hook 2")
+		},
+	)
+
+	t.Run("New", func(t *testing.T) {
+		order = nil
+		New("err")
+		assert.Empty(t, order)
+	})
+
+	t.Run("Wrap", func(t *testing.T) {
+		order = nil
+		err := Wrap(errors.New("origin"), func(*Error) {
+			order = append(order, "annotator")
+		})
+		assert.Equal(t, []string{"annotator", "hook 1", "hook 2"}, order)
+		assert.Equal(t, H{"version": "v1.0.0"}, Unwrap(err).Params)
+	})
+
+	t.Run("already wrapped", func(t *testing.T) {
+		order = nil
+		Wrap(Wrap(errors.New("origin")))
+		assert.Equal(t, []string{"hook 1", "hook 2", "hook 1", "hook 2"}, order)
+	})
+
+	t.Run("nil", func(t *testing.T) {
+		order = nil
+		Wrap(nil)
+		assert.Empty(t, order)
+	})
+}
+
+func TestHooks_Concurrency(t *testing.T) {
+	defer restoreHooks()()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			OnWrap(func(*Error) {})
+		}()
+		go func() {
+			defer wg.Done()
+			Wrap(errors.New("origin"))
+		}()
+	}
+	wg.Wait()
+}
+
+// restoreHooks returns a function that restores the registered hooks
+func restoreHooks() func() {
+	hooksMu.Lock()
+	defer hooksMu.Unlock()
+	create, wrap := createHooks, wrapHooks
+	return func() {
+		hooksMu.Lock()
+		defer hooksMu.Unlock()
+		createHooks, wrapHooks = create, wrap
+	}
+}
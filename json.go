@@ -0,0 +1,207 @@
+package fail
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// DecodedError is a root error rebuilt from a serialized form of *Error.
+// It keeps the type name and the message of the original root error.
+type DecodedError struct {
+	Type    string
+	Message string
+}
+
+// Error implements error interface
+func (e *DecodedError) Error() string {
+	return e.Message
+}
+
+// codeTypeCanonical is the "code_type" of CanonicalCode in the JSON representation,
+// which tells the code from other codes of the same name or value
+const codeTypeCanonical = "canonical"
+
+// jsonError is the JSON representation of *Error
+type jsonError struct {
+	Message     string            `json:"message"`
+	Error       string            `json:"error"`
+	Type        string            `json:"type,omitempty"`
+	Messages    []string          `json:"messages,omitempty"`
+	Code        interface{}       `json:"code,omitempty"`
+	CodeType    string            `json:"code_type,omitempty"`
+	Ignorable   bool              `json:"ignorable,omitempty"`
+	Severity    Severity          `json:"severity,omitempty"`
+	Retryable   bool              `json:"retryable,omitempty"`
+	RetryAfter  string            `json:"retry_after,omitempty"`
+	Tags        []string          `json:"tags,omitempty"`
+	Params      H                 `json:"params,omitempty"`
+	Kind        string            `json:"kind,omitempty"`
+	StackTrace  []jsonFrame       `json:"stack_trace,omitempty"`
+	CreatedAt   *time.Time        `json:"created_at,omitempty"`
+	WrappedAt   *time.Time        `json:"wrapped_at,omitempty"`
+	GoroutineID int64             `json:"goroutine_id,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Violations  []FieldViolation  `json:"violations,omitempty"`
+}
+
+type jsonFrame struct {
+	Func    string `json:"func"`
+	File    string `json:"file"`
+	Line    int64  `json:"line"`
+	Remote  bool   `json:"remote,omitempty"`
+	Service string `json:"service,omitempty"`
+	Host    string `json:"host,omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler.
+// The root error is serialized as its type name and message, with violations for *ValidationError.
+// CanonicalCode is serialized as its name with "code_type": "canonical".
+func (e *Error) MarshalJSON() ([]byte, error) {
+	je := jsonError{
+		Message:     e.Error(),
+		Error:       e.Err.Error(),
+		Type:        RootTypeName(e.Err),
+		Messages:    e.Messages,
+		Code:        e.Code,
+		Ignorable:   e.Ignorable,
+		Severity:    e.Severity,
+		Retryable:   e.Retryable,
+		Tags:        e.Tags,
+		Params:      e.Params,
+		GoroutineID: e.GoroutineID,
+		Labels:      e.Labels,
+	}
+	if _, ok := e.Code.(CanonicalCode); ok {
+		je.CodeType = codeTypeCanonical
+	}
+	if e.Kind != nil {
+		je.Kind = e.Kind.ID()
+	}
+	if verr, ok := e.Err.(*ValidationError); ok {
+		je.Violations = verr.Violations
+	}
+	if e.RetryAfter != 0 {
+		je.RetryAfter = e.RetryAfter.String()
+	}
+	if !e.CreatedAt.IsZero() {
+		je.CreatedAt = &e.CreatedAt
+	}
+	if !e.WrappedAt.IsZero() {
+		je.WrappedAt = &e.WrappedAt
+	}
+	for _, f := range e.StackTrace {
+		je.StackTrace = append(je.StackTrace, jsonFrame{
+			Func:    f.Func,
+			File:    f.File,
+			Line:    f.Line,
+			Remote:  f.Remote,
+			Service: f.Origin.Service,
+			Host:    f.Origin.Host,
+		})
+	}
+	return json.Marshal(je)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+// The root error is rebuilt as *DecodedError, or *ValidationError if it has violations, and the kind is rebuilt from its ID
+// so that it still matches the original kind with errors.Is.
+// CanonicalCode is rebuilt from its name by "code_type", so that it's still recognized as a canonical code.
+func (e *Error) UnmarshalJSON(data []byte) error {
+	var je jsonError
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&je); err != nil {
+		return err
+	}
+
+	*e = Error{
+		Err:         &DecodedError{Type: je.Type, Message: je.Error},
+		Messages:    je.Messages,
+		Code:        decodeJSONValue(je.Code),
+		Ignorable:   je.Ignorable,
+		Severity:    je.Severity,
+		Retryable:   je.Retryable,
+		Tags:        je.Tags,
+		Params:      je.Params,
+		GoroutineID: je.GoroutineID,
+		Labels:      je.Labels,
+	}
+	if len(je.Violations) > 0 {
+		e.Err = &ValidationError{Violations: je.Violations}
+	}
+	if je.CodeType == codeTypeCanonical {
+		var code CanonicalCode
+		if err := code.UnmarshalText([]byte(fmt.Sprint(je.Code))); err != nil {
+			return err
+		}
+		e.Code = code
+	}
+	if je.RetryAfter != "" {
+		d, err := time.ParseDuration(je.RetryAfter)
+		if err != nil {
+			return err
+		}
+		e.RetryAfter = d
+	}
+	if je.CreatedAt != nil {
+		e.CreatedAt = *je.CreatedAt
+	}
+	if je.WrappedAt != nil {
+		e.WrappedAt = *je.WrappedAt
+	}
+	for k, v := range e.Params {
+		e.Params[k] = decodeJSONValue(v)
+	}
+	if je.Kind != "" {
+		e.Kind = Define(je.Kind)
+	}
+	for _, f := range je.StackTrace {
+		e.StackTrace = append(e.StackTrace, Frame{
+			Func:   f.Func,
+			File:   f.File,
+			Line:   f.Line,
+			Remote: f.Remote,
+			Origin: Origin{Service: f.Service, Host: f.Host},
+		})
+	}
+	return nil
+}
+
+// decodeJSONValue converts json.Number in the decoded value into int if possible, or float64 otherwise
+func decodeJSONValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, e := range v {
+			v[k] = decodeJSONValue(e)
+		}
+		return v
+	case []interface{}:
+		for i, e := range v {
+			v[i] = decodeJSONValue(e)
+		}
+		return v
+	}
+
+	n, ok := v.(json.Number)
+	if !ok {
+		return v
+	}
+	if i, err := n.Int64(); err == nil && int64(int(i)) == i {
+		return int(i)
+	}
+	if f, err := n.Float64(); err == nil {
+		return f
+	}
+	return n.String()
+}
+
+// RootTypeName returns the type name of the root error, such as "*errors.errorString".
+// It returns the original type name for *DecodedError, so that the name survives serialization.
+func RootTypeName(err error) string {
+	if de, ok := err.(*DecodedError); ok {
+		return de.Type
+	}
+	return fmt.Sprintf("%T", err)
+}
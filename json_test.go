@@ -0,0 +1,216 @@
+package fail
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestError_MarshalJSON(t *testing.T) {
+	err := &Error{
+		Err:       errors.New("origin"),
+		Messages:  []string{"message 1"},
+		Code:      500,
+		Ignorable: true,
+		Tags:      []string{"http"},
+		Params:    H{"foo": 1},
+		StackTrace: StackTrace{
+			{Func: "Handler", File: "server.go", Line: 42, Remote: true, Origin: Origin{Service: "users", Host: "users-1"}},
+			{Func: "main", File: "main.go", Line: 179},
+		},
+		Kind:        Define("internal"),
+		CreatedAt:   time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+		WrappedAt:   time.Date(2020, 1, 1, 0, 0, 1, 0, time.UTC),
+		GoroutineID: 18,
+		Labels:      map[string]string{"handler": "users"},
+	}
+
+	data, jsonErr := json.Marshal(err)
+	assert.NoError(t, jsonErr)
+	assert.JSONEq(t, `{
+		"message": "message 1: origin",
+		"error": "origin",
+		"type": "*errors.errorString",
+		"messages": ["message 1"],
+		"code": 500,
+		"ignorable": true,
+		"tags": ["http"],
+		"params": {"foo": 1},
+		"kind": "internal",
+		"stack_trace": [
+			{"func": "Handler", "file": "server.go", "line": 42, "remote": true, "service": "users", "host": "users-1"},
+			{"func": "main", "file": "main.go", "line": 179}
+		],
+		"created_at": "2020-01-01T00:00:00Z",
+		"wrapped_at": "2020-01-01T00:00:01Z",
+		"goroutine_id": 18,
+		"labels": {"handler": "users"}
+	}`, string(data))
+
+	t.Run("minimum", func(t *testing.T) {
+		data, jsonErr := json.Marshal(&Error{Err: errors.New("origin")})
+		assert.NoError(t, jsonErr)
+		assert.JSONEq(t, `{"message": "origin", "error": "origin", "type": "*errors.errorString"}`, string(data))
+	})
+}
+
+// TestError_MarshalJSON_Shape locks the keys of the JSON representation,
+// which replaced the default encoding of the exported fields such as "Err" and "StackTrace".
+func TestError_MarshalJSON_Shape(t *testing.T) {
+	err := &Error{
+		Err:        &ValidationError{Violations: []FieldViolation{{Field: "name", Message: "is required"}}},
+		Messages:   []string{"message 1"},
+		Code:       CodeInvalidArgument,
+		Ignorable:  true,
+		Severity:   SeverityWarning,
+		Retryable:  true,
+		RetryAfter: time.Second,
+		Tags:       []string{"http"},
+		Params:     H{"foo": 1},
+		StackTrace: StackTrace{
+			{Func: "Handler", File: "server.go", Line: 42, Remote: true, Origin: Origin{Service: "users", Host: "users-1"}},
+		},
+		Kind:        Define("invalid"),
+		CreatedAt:   time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+		WrappedAt:   time.Date(2020, 1, 1, 0, 0, 1, 0, time.UTC),
+		GoroutineID: 18,
+		Labels:      map[string]string{"handler": "users"},
+	}
+
+	data, jsonErr := json.Marshal(err)
+	assert.NoError(t, jsonErr)
+
+	var fields map[string]json.RawMessage
+	assert.NoError(t, json.Unmarshal(data, &fields))
+	assert.ElementsMatch(t, []string{
+		"message", "error", "type", "messages", "code", "code_type", "ignorable", "severity", "retryable", "retry_after",
+		"tags", "params", "kind", "stack_trace", "created_at", "wrapped_at", "goroutine_id", "labels", "violations",
+	}, jsonKeys(fields))
+
+	var frames []map[string]json.RawMessage
+	assert.NoError(t, json.Unmarshal(fields["stack_trace"], &frames))
+	if assert.Len(t, frames, 1) {
+		assert.ElementsMatch(t, []string{"func", "file", "line", "remote", "service", "host"}, jsonKeys(frames[0]))
+	}
+
+	var violations []map[string]json.RawMessage
+	assert.NoError(t, json.Unmarshal(fields["violations"], &violations))
+	if assert.Len(t, violations, 1) {
+		assert.ElementsMatch(t, []string{"field", "constraint", "message"}, jsonKeys(violations[0]))
+	}
+}
+
+func jsonKeys(m map[string]json.RawMessage) []string {
+	ks := make([]string, 0, len(m))
+	for k := range m {
+		ks = append(ks, k)
+	}
+	return ks
+}
+
+func TestError_UnmarshalJSON(t *testing.T) {
+	t.Run("round trip", func(t *testing.T) {
+		kind := Define("internal")
+		err0 := Wrap(kind.New("origin"), WithMessage("message 1"), WithCode(500), WithTags("http"), WithParams(H{
+			"int":    1,
+			"float":  1.5,
+			"nested": map[string]interface{}{"int": 2},
+		}))
+
+		data, jsonErr := json.Marshal(err0)
+		assert.NoError(t, jsonErr)
+
+		var err1 Error
+		assert.NoError(t, json.Unmarshal(data, &err1))
+
+		failErr := Unwrap(err0)
+		assert.Equal(t, &DecodedError{Type: "*errors.errorString", Message: "origin"}, err1.Err)
+		assert.Equal(t, failErr.Error(), err1.Error())
+		assert.Equal(t, failErr.Messages, err1.Messages)
+		assert.Equal(t, 500, err1.Code)
+		assert.Equal(t, failErr.Tags, err1.Tags)
+		assert.Equal(t, H{"int": 1, "float": 1.5, "nested": map[string]interface{}{"int": 2}}, err1.Params)
+		assert.Equal(t, failErr.StackTrace, err1.StackTrace)
+		assert.True(t, failErr.CreatedAt.Equal(err1.CreatedAt))
+		assert.True(t, failErr.WrappedAt.Equal(err1.WrappedAt))
+		assert.True(t, err1.Is(kind))
+		assert.Equal(t, failErr.Fingerprint(), err1.Fingerprint())
+	})
+
+	t.Run("canonical code", func(t *testing.T) {
+		data, jsonErr := json.Marshal(Wrap(New("origin"), WithCode(CodeUnavailable)))
+		assert.NoError(t, jsonErr)
+		assert.Contains(t, string(data), `"code":"Unavailable","code_type":"canonical"`)
+
+		var err Error
+		assert.NoError(t, json.Unmarshal(data, &err))
+		assert.Equal(t, CodeUnavailable, err.Code)
+		assert.True(t, IsRetryable(&err))
+		assert.True(t, Match.Code(CodeUnavailable)(&err))
+	})
+
+	t.Run("string code", func(t *testing.T) {
+		data, jsonErr := json.Marshal(Wrap(New("origin"), WithCode("Unavailable")))
+		assert.NoError(t, jsonErr)
+
+		var err Error
+		assert.NoError(t, json.Unmarshal(data, &err))
+		assert.Equal(t, "Unavailable", err.Code)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		var err Error
+		assert.Error(t, json.Unmarshal([]byte(`{"message": 1}`), &err))
+		assert.Error(t, json.Unmarshal([]byte(`{"code": "Unavailabel", "code_type": "canonical"}`), &err))
+	})
+}
+
+func TestError_Fingerprint(t *testing.T) {
+	stackTrace := StackTrace{
+		{Func: "f1", File: "main.go", Line: 157},
+		{Func: "main", File: "main.go", Line: 179},
+	}
+
+	err := &Error{Err: errors.New("origin"), StackTrace: stackTrace}
+
+	assert.Len(t, err.Fingerprint(), 16)
+
+	t.Run("same cause", func(t *testing.T) {
+		other := &Error{
+			Err:      errors.New("other message"),
+			Messages: []string{"message"},
+			Params:   H{"foo": 1},
+			StackTrace: StackTrace{
+				{Func: "f1", File: "main.go", Line: 158},
+				{Func: "main", File: "main.go", Line: 180},
+			},
+		}
+		assert.Equal(t, err.Fingerprint(), other.Fingerprint())
+	})
+
+	t.Run("different cause", func(t *testing.T) {
+		others := []*Error{
+			{Err: &DecodedError{Message: "origin"}, StackTrace: stackTrace},
+			{Err: errors.New("origin"), StackTrace: stackTrace, Kind: Define("internal")},
+			{Err: errors.New("origin"), StackTrace: stackTrace[1:]},
+			{Err: errors.New("origin")},
+		}
+		for _, other := range others {
+			assert.NotEqual(t, err.Fingerprint(), other.Fingerprint())
+		}
+	})
+
+	t.Run("without stack trace", func(t *testing.T) {
+		err1 := &Error{Err: errors.New("origin 1")}
+		err2 := &Error{Err: errors.New("origin 2")}
+		assert.NotEqual(t, err1.Fingerprint(), err2.Fingerprint())
+	})
+}
+
+func TestRootTypeName(t *testing.T) {
+	assert.Equal(t, "*errors.errorString", RootTypeName(errors.New("origin")))
+	assert.Equal(t, "*net.OpError", RootTypeName(&DecodedError{Type: "*net.OpError", Message: "dial tcp"}))
+}
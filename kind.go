@@ -0,0 +1,85 @@
+package fail
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Kind is a class of errors that share a machine-readable ID and preset annotators.
+// Errors created or wrapped by a kind match the kind with errors.Is.
+type Kind struct {
+	id         string
+	annotators []Annotator
+}
+
+// Define returns a new kind with the ID and the annotators
+// that are applied to every error of the kind.
+//
+//	var ErrUserNotFound = fail.Define("user_not_found", fail.WithCode(404), fail.WithIgnorable())
+func Define(id string, annotators ...Annotator) *Kind {
+	return &Kind{
+		id:         id,
+		annotators: annotators,
+	}
+}
+
+// ID returns the ID of the kind
+func (k *Kind) ID() string {
+	return k.id
+}
+
+// Error implements error interface.
+// It returns the ID of the kind.
+func (k *Kind) Error() string {
+	return k.id
+}
+
+// New returns an error of the kind that formats as the given text.
+// It also records the stack trace at the point it was called.
+func (k *Kind) New(text string) error {
+	err := &Error{Err: errors.New(text), CreatedAt: now()}
+	withStackTrace(0)(err)
+	runHooks(&createHooks, err)
+	WithKind(k)(err)
+	return err
+}
+
+// Errorf returns an error of the kind that formats according to a format specifier.
+// It also records the stack trace at the point it was called.
+func (k *Kind) Errorf(format string, args ...interface{}) error {
+	return k.errorf(0, format, args)
+}
+
+// ErrorfSkip is Errorf for constructors that create errors on behalf of their callers.
+// The skip is the number of frames to skip above the caller of ErrorfSkip,
+// so that the stack trace starts from the caller of the constructor with 1.
+func (k *Kind) ErrorfSkip(skip int, format string, args ...interface{}) error {
+	return k.errorf(skip, format, args)
+}
+
+// errorf is the implementation of Errorf.
+// The offset is the number of extra frames between the caller and errorf itself.
+func (k *Kind) errorf(offset int, format string, args []interface{}) error {
+	err := &Error{Err: fmt.Errorf(format, args...), CreatedAt: now()}
+	withStackTrace(offset + 1)(err)
+	runHooks(&createHooks, err)
+	WithKind(k)(err)
+	return err
+}
+
+// Wrap is like fail.Wrap, but it also annotates the error with the kind.
+// The annotators of the kind are applied before the specified annotators.
+// It returns nil if err is nil.
+func (k *Kind) Wrap(err error, annotators ...Annotator) error {
+	return wrap(err, 0, append([]Annotator{WithKind(k)}, annotators...))
+}
+
+// WithKind annotates an error with the kind and its annotators
+func WithKind(k *Kind) Annotator {
+	return func(err *Error) {
+		err.Kind = k
+		for _, f := range k.annotators {
+			f(err)
+		}
+	}
+}
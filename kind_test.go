@@ -0,0 +1,129 @@
+package fail
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var errTestKind = Define("test_kind", WithCode(404), WithTags("user"), WithIgnorable())
+
+func TestKind_New(t *testing.T) {
+	err := errTestKind.New("user not found")
+	assert.Equal(t, "user not found", err.Error())
+
+	failErr := Unwrap(err)
+	assert.Equal(t, errTestKind, failErr.Kind)
+	assert.Equal(t, 404, failErr.Code)
+	assert.Equal(t, []string{"user"}, failErr.Tags)
+	assert.Equal(t, true, failErr.Ignorable)
+	assert.NotEmpty(t, failErr.StackTrace)
+	assert.Equal(t, "TestKind_New", failErr.StackTrace[0].Func)
+}
+
+func TestKind_Errorf(t *testing.T) {
+	err := errTestKind.Errorf("user %d not found", 1)
+	assert.Equal(t, "user 1 not found", err.Error())
+
+	failErr := Unwrap(err)
+	assert.Equal(t, errTestKind, failErr.Kind)
+	assert.Equal(t, 404, failErr.Code)
+	assert.NotEmpty(t, failErr.StackTrace)
+	assert.Equal(t, "TestKind_Errorf", failErr.StackTrace[0].Func)
+}
+
+func newTestKindError(id int) error {
+	return errTestKind.ErrorfSkip(1, "user %d not found", id)
+}
+
+func TestKind_ErrorfSkip(t *testing.T) {
+	err := newTestKindError(1)
+	assert.Equal(t, "user 1 not found", err.Error())
+
+	failErr := Unwrap(err)
+	assert.Equal(t, errTestKind, failErr.Kind)
+	assert.Equal(t, "TestKind_ErrorfSkip", failErr.StackTrace[0].Func)
+
+	failErr = Unwrap(errTestKind.ErrorfSkip(0, "origin"))
+	assert.Equal(t, "TestKind_ErrorfSkip", failErr.StackTrace[0].Func)
+}
+
+func TestKind_Wrap(t *testing.T) {
+	t.Run("nil", func(t *testing.T) {
+		err := errTestKind.Wrap(nil)
+		assert.Nil(t, err)
+	})
+
+	t.Run("bare", func(t *testing.T) {
+		err0 := errors.New("origin")
+
+		err1 := wrapKindOrigin(err0)
+		assert.Equal(t, "message: origin", err1.Error())
+
+		failErr := Unwrap(err1)
+		assert.Equal(t, err0, failErr.Err)
+		assert.Equal(t, errTestKind, failErr.Kind)
+		assert.Equal(t, 404, failErr.Code)
+		assert.NotEmpty(t, failErr.StackTrace)
+		assert.Equal(t, "wrapKindOrigin", failErr.StackTrace[0].Func)
+	})
+
+	t.Run("annotators override kind", func(t *testing.T) {
+		err := errTestKind.Wrap(errors.New("origin"), WithCode(410))
+
+		failErr := Unwrap(err)
+		assert.Equal(t, errTestKind, failErr.Kind)
+		assert.Equal(t, 410, failErr.Code)
+	})
+
+	t.Run("already wrapped", func(t *testing.T) {
+		err := Wrap(errTestKind.Wrap(errors.New("origin")), WithMessage("message"))
+
+		failErr := Unwrap(err)
+		assert.Equal(t, errTestKind, failErr.Kind)
+		assert.Equal(t, 404, failErr.Code)
+	})
+}
+
+func TestKind_ID(t *testing.T) {
+	assert.Equal(t, "test_kind", errTestKind.ID())
+	assert.Equal(t, "test_kind", errTestKind.Error())
+}
+
+func TestError_Is(t *testing.T) {
+	err := Unwrap(errTestKind.New("user not found"))
+	assert.True(t, err.Is(errTestKind))
+	assert.True(t, err.Is(Define("test_kind")))
+	assert.False(t, err.Is(Define("other_kind")))
+	assert.False(t, err.Is(errors.New("test_kind")))
+	assert.False(t, Unwrap(New("err")).Is(errTestKind))
+}
+
+func wrapKindOrigin(err error) error {
+	return errTestKind.Wrap(err, WithMessage("message"))
+}
+
+func TestKind_ErrorsIs(t *testing.T) {
+	errOther := Define("other_kind")
+
+	errs := map[string]error{
+		"new":        errTestKind.New("user not found"),
+		"wrap":       errTestKind.Wrap(errFunc0e()),
+		"fail.Wrap":  Wrap(errTestKind.New("user not found"), WithMessage("wrapped")),
+		"fmt.Errorf": fmt.Errorf("wrapped: %w", Wrap(errTestKind.New("user not found"))),
+		"WithKind":   Wrap(errFunc0e(), WithKind(errTestKind)),
+	}
+
+	for name, err := range errs {
+		t.Run(name, func(t *testing.T) {
+			if !errors.Is(err, errTestKind) {
+				t.Errorf("error should be %v", errTestKind)
+			}
+			if errors.Is(err, errOther) {
+				t.Errorf("error should not be %v", errOther)
+			}
+		})
+	}
+}
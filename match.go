@@ -0,0 +1,293 @@
+package fail
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strconv"
+	"sync"
+)
+
+// Matcher reports whether an error matches a condition.
+// It decides how to treat errors in reporters, mappers of status codes and sampling policies.
+//
+//	m := fail.Match.Code(fail.CodeNotFound).Or(fail.Match.Tag("db")).And(fail.Match.RootIs(io.EOF))
+type Matcher func(err error) bool
+
+// And returns a matcher that matches if the matcher and all of the others match
+func (m Matcher) And(others ...Matcher) Matcher {
+	return func(err error) bool {
+		if !m(err) {
+			return false
+		}
+		for _, o := range others {
+			if !o(err) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// Or returns a matcher that matches if the matcher or any of the others matches
+func (m Matcher) Or(others ...Matcher) Matcher {
+	return func(err error) bool {
+		if m(err) {
+			return true
+		}
+		for _, o := range others {
+			if o(err) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Not returns a matcher that matches if the matcher doesn't match
+func (m Matcher) Not() Matcher {
+	return func(err error) bool {
+		return !m(err)
+	}
+}
+
+// Matchers builds matchers. Use it through Match.
+type Matchers struct{}
+
+// Match is the entry point to build matchers, such as Match.Code(fail.CodeNotFound)
+var Match Matchers
+
+// Any returns a matcher that matches any non-nil error
+func (Matchers) Any() Matcher {
+	return func(err error) bool {
+		return err != nil
+	}
+}
+
+// Code returns a matcher that matches errors that have any of the codes
+func (Matchers) Code(codes ...interface{}) Matcher {
+	return func(err error) bool {
+		failErr := Unwrap(err)
+		if failErr == nil || failErr.Code == nil {
+			return false
+		}
+		for _, code := range codes {
+			if reflect.DeepEqual(failErr.Code, code) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Tag returns a matcher that matches errors that have any of the tags
+func (Matchers) Tag(tags ...string) Matcher {
+	return func(err error) bool {
+		return HasAnyTag(err, tags...)
+	}
+}
+
+// TagQuery returns a matcher that matches errors whose tags match the query
+func (Matchers) TagQuery(q *TagQuery) Matcher {
+	return func(err error) bool {
+		return err != nil && q.MatchError(err)
+	}
+}
+
+// Kind returns a matcher that matches errors of the kind
+func (Matchers) Kind(k *Kind) Matcher {
+	return func(err error) bool {
+		return errors.Is(err, k)
+	}
+}
+
+// RootIs returns a matcher that matches errors whose root error is the target with errors.Is
+func (Matchers) RootIs(target error) Matcher {
+	return func(err error) bool {
+		if failErr := Unwrap(err); failErr != nil {
+			err = failErr.Err
+		}
+		return err != nil && errors.Is(err, target)
+	}
+}
+
+// Is returns a matcher that matches errors that are the target with errors.Is
+func (Matchers) Is(target error) Matcher {
+	return func(err error) bool {
+		return err != nil && errors.Is(err, target)
+	}
+}
+
+// Severity returns a matcher that matches errors whose effective severity is at least the severity
+func (Matchers) Severity(s Severity) Matcher {
+	return func(err error) bool {
+		if err == nil {
+			return false
+		}
+		failErr := Unwrap(err)
+		if failErr == nil {
+			failErr = &Error{Err: err}
+		}
+		return failErr.EffectiveSeverity() >= s
+	}
+}
+
+// Retryable returns a matcher that matches retryable errors as IsRetryable
+func (Matchers) Retryable() Matcher {
+	return IsRetryable
+}
+
+// Ignorable returns a matcher that matches ignorable errors
+func (Matchers) Ignorable() Matcher {
+	return func(err error) bool {
+		failErr := Unwrap(err)
+		return failErr != nil && failErr.Ignorable
+	}
+}
+
+var (
+	matchTargetsMu sync.RWMutex
+	matchTargets   = map[string]error{
+		"io.EOF":                   io.EOF,
+		"io.ErrUnexpectedEOF":      io.ErrUnexpectedEOF,
+		"context.Canceled":         context.Canceled,
+		"context.DeadlineExceeded": context.DeadlineExceeded,
+		"os.ErrNotExist":           os.ErrNotExist,
+		"os.ErrExist":              os.ErrExist,
+		"os.ErrPermission":         os.ErrPermission,
+	}
+)
+
+// RegisterMatchTarget registers an error by the name referred from "root:" and "is:" of ParseMatcher,
+// such as "sql.ErrNoRows". Errors of io, context and os are registered by default.
+func RegisterMatchTarget(name string, target error) {
+	matchTargetsMu.Lock()
+	defer matchTargetsMu.Unlock()
+	matchTargets[name] = target
+}
+
+// ParseMatcher parses the text syntax of matchers, which is loadable from configuration.
+// Terms are combined with "!", "&&" and "||" in order of precedence, and grouped with parentheses, as TagQuery.
+//
+//	code:NotFound || code:404   Match.Code, with a name of CanonicalCode, an integer or a string
+//	tag:db, tag:team:*          Match.TagQuery of the tag
+//	kind:user_not_found         Match.Kind of the ID
+//	root:io.EOF, is:io.EOF      Match.RootIs and Match.Is of an error registered by RegisterMatchTarget
+//	severity:warning            Match.Severity
+//	retryable, ignorable, any   Match.Retryable, Match.Ignorable and Match.Any
+func ParseMatcher(s string) (Matcher, error) {
+	node, err := parseQuery(s)
+	if err == nil {
+		var m Matcher
+		if m, err = compileMatcher(node); err == nil {
+			return m, nil
+		}
+	}
+	return nil, fmt.Errorf("invalid matcher %q: %v", s, err)
+}
+
+// MustParseMatcher is like ParseMatcher, but it panics if the text is invalid
+func MustParseMatcher(s string) Matcher {
+	m, err := ParseMatcher(s)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+func compileMatcher(node queryNode) (Matcher, error) {
+	switch n := node.(type) {
+	case queryNot:
+		m, err := compileMatcher(n.node)
+		if err != nil {
+			return nil, err
+		}
+		return m.Not(), nil
+	case queryAnd:
+		left, right, err := compileMatchers(n.left, n.right)
+		if err != nil {
+			return nil, err
+		}
+		return left.And(right), nil
+	case queryOr:
+		left, right, err := compileMatchers(n.left, n.right)
+		if err != nil {
+			return nil, err
+		}
+		return left.Or(right), nil
+	case queryTerm:
+		return compileTerm(string(n))
+	}
+	return nil, fmt.Errorf("unexpected %v", node)
+}
+
+func compileMatchers(left, right queryNode) (Matcher, Matcher, error) {
+	l, err := compileMatcher(left)
+	if err != nil {
+		return nil, nil, err
+	}
+	r, err := compileMatcher(right)
+	if err != nil {
+		return nil, nil, err
+	}
+	return l, r, nil
+}
+
+// compileTerm returns the matcher of a term of ParseMatcher
+func compileTerm(term string) (Matcher, error) {
+	switch term {
+	case "any":
+		return Match.Any(), nil
+	case "retryable":
+		return Match.Retryable(), nil
+	case "ignorable":
+		return Match.Ignorable(), nil
+	}
+
+	key, value, ok := SplitTag(term)
+	if !ok || value == "" {
+		return nil, fmt.Errorf("unknown matcher %q", term)
+	}
+	switch key {
+	case "code":
+		return Match.Code(parseCode(value)), nil
+	case "tag":
+		return Match.TagQuery(&TagQuery{src: value, node: queryTerm(value)}), nil
+	case "kind":
+		return Match.Kind(Define(value)), nil
+	case "root", "is":
+		matchTargetsMu.RLock()
+		target, ok := matchTargets[value]
+		matchTargetsMu.RUnlock()
+		if !ok {
+			return nil, fmt.Errorf("unknown target %q", value)
+		}
+		if key == "root" {
+			return Match.RootIs(target), nil
+		}
+		return Match.Is(target), nil
+	case "severity":
+		s, err := ParseSeverity(value)
+		if err != nil {
+			return nil, err
+		}
+		return Match.Severity(s), nil
+	}
+	return nil, fmt.Errorf("unknown matcher %q", term)
+}
+
+// parseCode returns the CanonicalCode of the name, the int of the number, or the string as is
+func parseCode(s string) interface{} {
+	for code, name := range canonicalCodeNames {
+		if name == s {
+			return code
+		}
+	}
+	if n, err := strconv.Atoi(s); err == nil {
+		return n
+	}
+	return s
+}
@@ -0,0 +1,118 @@
+package fail
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatcher(t *testing.T) {
+	errNotFound := Wrap(io.EOF, WithCode(CodeNotFound), WithTags("db"))
+	errConflict := Wrap(errors.New("conflict"), WithCode(409), WithTags("user", "team:payment"), WithIgnorable())
+	errTimeout := Wrap(fmt.Errorf("query: %w", context.DeadlineExceeded), WithTags("db"), WithSeverity(SeverityFatal))
+	errKind := Define("user_not_found").New("user not found")
+	errPlain := errors.New("plain")
+
+	cases := []struct {
+		test    string
+		matcher Matcher
+		want    []error
+	}{
+		{test: "any", matcher: Match.Any(), want: []error{errNotFound, errConflict, errTimeout, errKind, errPlain}},
+		{test: "code", matcher: Match.Code(CodeNotFound, 409), want: []error{errNotFound, errConflict}},
+		{test: "tag", matcher: Match.Tag("db"), want: []error{errNotFound, errTimeout}},
+		{test: "tag query", matcher: Match.TagQuery(MustParseTagQuery("team:*")), want: []error{errConflict}},
+		{test: "kind", matcher: Match.Kind(Define("user_not_found")), want: []error{errKind}},
+		{test: "root is", matcher: Match.RootIs(io.EOF), want: []error{errNotFound}},
+		{test: "root is wrapped", matcher: Match.RootIs(context.DeadlineExceeded), want: []error{errTimeout}},
+		{test: "severity", matcher: Match.Severity(SeverityError), want: []error{errNotFound, errTimeout, errKind, errPlain}},
+		{test: "retryable", matcher: Match.Retryable(), want: []error{errTimeout}},
+		{test: "ignorable", matcher: Match.Ignorable(), want: []error{errConflict}},
+		{test: "not", matcher: Match.Tag("db").Not(), want: []error{errConflict, errKind, errPlain}},
+		{
+			test:    "or and",
+			matcher: Match.Code(CodeNotFound).Or(Match.Tag("db")).And(Match.RootIs(io.EOF)),
+			want:    []error{errNotFound},
+		},
+		{
+			test:    "variadic",
+			matcher: Match.Tag("db").And(Match.Retryable(), Match.Severity(SeverityFatal)).Or(Match.Ignorable(), Match.Kind(Define("user_not_found"))),
+			want:    []error{errConflict, errTimeout, errKind},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.test, func(t *testing.T) {
+			var got []error
+			for _, err := range []error{errNotFound, errConflict, errTimeout, errKind, errPlain} {
+				if c.matcher(err) {
+					got = append(got, err)
+				}
+			}
+			assert.Equal(t, c.want, got)
+		})
+	}
+}
+
+func TestParseMatcher(t *testing.T) {
+	errNotFound := Wrap(io.EOF, WithCode(CodeNotFound), WithTags("db"))
+	errConflict := Wrap(errors.New("conflict"), WithCode(409), WithTags("user", "team:payment"), WithIgnorable())
+	errTimeout := Wrap(context.DeadlineExceeded, WithCode("timeout"), WithTags("db"))
+	errKind := Define("user_not_found").New("user not found")
+
+	cases := []struct {
+		text string
+		want []error
+	}{
+		{text: "any", want: []error{errNotFound, errConflict, errTimeout, errKind}},
+		{text: "code:NotFound || code:409", want: []error{errNotFound, errConflict}},
+		{text: "code:timeout", want: []error{errTimeout}},
+		{text: "tag:db && !root:io.EOF", want: []error{errTimeout}},
+		{text: "tag:team:*", want: []error{errConflict}},
+		{text: "kind:user_not_found", want: []error{errKind}},
+		{text: "is:context.DeadlineExceeded", want: []error{errTimeout}},
+		{text: "severity:error && !retryable", want: []error{errNotFound, errKind}},
+		{text: "ignorable || (code:NotFound || tag:db) && root:io.EOF", want: []error{errNotFound, errConflict}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.text, func(t *testing.T) {
+			m, err := ParseMatcher(c.text)
+			if assert.NoError(t, err) {
+				var got []error
+				for _, err := range []error{errNotFound, errConflict, errTimeout, errKind} {
+					if m(err) {
+						got = append(got, err)
+					}
+				}
+				assert.Equal(t, c.want, got)
+			}
+		})
+	}
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, text := range []string{"", "code:", "unknown", "foo:bar", "root:io.Nope", "severity:huge", "tag:db &&"} {
+			_, err := ParseMatcher(text)
+			assert.Error(t, err, text)
+		}
+		assert.Panics(t, func() { MustParseMatcher("(") })
+	})
+}
+
+func TestRegisterMatchTarget(t *testing.T) {
+	errCustom := errors.New("custom")
+	RegisterMatchTarget("test.errCustom", errCustom)
+	defer func() {
+		matchTargetsMu.Lock()
+		defer matchTargetsMu.Unlock()
+		delete(matchTargets, "test.errCustom")
+	}()
+
+	m := MustParseMatcher("root:test.errCustom")
+	assert.True(t, m(Wrap(errCustom)))
+	assert.False(t, m(Wrap(errors.New("custom"))))
+}
@@ -0,0 +1,107 @@
+package fail
+
+import (
+	"errors"
+	"reflect"
+)
+
+// ParamKey is a key of a param with the type of its value.
+// Values are stored in Params with the name of the key, so they are also readable as H.
+type ParamKey[T any] struct {
+	name string
+}
+
+// NewParamKey creates a key of a param of the type T
+//
+//	var UserID = fail.NewParamKey[int64]("user_id")
+func NewParamKey[T any](name string) ParamKey[T] {
+	return ParamKey[T]{name: name}
+}
+
+// Name returns the name of the key in Params
+func (k ParamKey[T]) Name() string {
+	return k.name
+}
+
+// WithParamKey annotates an error with a value of the key
+func WithParamKey[T any](key ParamKey[T], value T) Annotator {
+	return WithParam(key.name, value)
+}
+
+// Get returns the value of the key from the first *Error in the chain of the error that has it.
+// The chain is followed through errors of pkg/errors as Unwrap, and through errors.Unwrap.
+// Numbers are converted into T if T is a numeric type, since decoded params have int or float64 values.
+// It returns false if no error in the chain has the value of the type.
+func Get[T any](err error, key ParamKey[T]) (T, bool) {
+	for err != nil {
+		failErr := Unwrap(err)
+		if failErr == nil {
+			err = errors.Unwrap(err)
+			continue
+		}
+		if v, ok := failErr.Params[key.name]; ok {
+			if value, ok := convertParam[T](v); ok {
+				return value, true
+			}
+		}
+		err = failErr.Err
+	}
+
+	var zero T
+	return zero, false
+}
+
+// convertParam returns the value as T, converting numbers between numeric types
+func convertParam[T any](v interface{}) (T, bool) {
+	if value, ok := v.(T); ok {
+		return value, true
+	}
+
+	var zero T
+	rv := reflect.ValueOf(v)
+	rt := reflect.TypeOf(zero)
+	if !rv.IsValid() || rt == nil || !isNumber(rv.Kind()) || !isNumber(rt.Kind()) {
+		return zero, false
+	}
+	if isUnsigned(rt.Kind()) && isNegative(rv) {
+		return zero, false
+	}
+
+	converted := rv.Convert(rt)
+	if converted.Convert(rv.Type()).Interface() != v {
+		// loses precision
+		return zero, false
+	}
+	return converted.Interface().(T), true
+}
+
+// isNumber reports whether the kind is an integer or a floating-point number
+func isNumber(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// isUnsigned reports whether the kind is an unsigned integer
+func isUnsigned(k reflect.Kind) bool {
+	switch k {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return true
+	}
+	return false
+}
+
+// isNegative reports whether the number is negative
+func isNegative(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() < 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() < 0
+	}
+	return false
+}
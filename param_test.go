@@ -0,0 +1,95 @@
+package fail
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	pkgerrors "github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	paramUserID = NewParamKey[int64]("user_id")
+	paramName   = NewParamKey[string]("name")
+)
+
+func TestWithParamKey(t *testing.T) {
+	err := Unwrap(Wrap(errors.New("origin"), WithParamKey(paramUserID, 1), WithParamKey(paramName, "foo")))
+	assert.Equal(t, H{"user_id": int64(1), "name": "foo"}, err.Params)
+	assert.Equal(t, "user_id", paramUserID.Name())
+}
+
+func TestGet(t *testing.T) {
+	t.Run("found", func(t *testing.T) {
+		err := Wrap(errors.New("origin"), WithParamKey(paramUserID, 1))
+
+		id, ok := Get(err, paramUserID)
+		assert.True(t, ok)
+		assert.Equal(t, int64(1), id)
+	})
+
+	t.Run("chain", func(t *testing.T) {
+		inner := Wrap(errors.New("origin"), WithParamKey(paramUserID, 1))
+		err := Wrap(fmt.Errorf("wrapped: %w", inner), WithParamKey(paramName, "foo"))
+
+		id, ok := Get(err, paramUserID)
+		assert.True(t, ok)
+		assert.Equal(t, int64(1), id)
+
+		name, ok := Get(err, paramName)
+		assert.True(t, ok)
+		assert.Equal(t, "foo", name)
+	})
+
+	t.Run("pkg/errors", func(t *testing.T) {
+		inner := Wrap(errors.New("origin"), WithParamKey(paramUserID, 1))
+		err := fmt.Errorf("wrapped: %w", pkgerrors.Wrap(inner, "message"))
+
+		id, ok := Get(err, paramUserID)
+		assert.True(t, ok)
+		assert.Equal(t, int64(1), id)
+	})
+
+	t.Run("decoded", func(t *testing.T) {
+		data, _ := json.Marshal(Wrap(errors.New("origin"), WithParamKey(paramUserID, 1)))
+		var err Error
+		assert.NoError(t, json.Unmarshal(data, &err))
+
+		id, ok := Get(&err, paramUserID)
+		assert.True(t, ok)
+		assert.Equal(t, int64(1), id)
+	})
+
+	t.Run("mismatched type", func(t *testing.T) {
+		err := Wrap(errors.New("origin"), WithParams(H{"user_id": "foo", "name": 1}))
+
+		_, ok := Get(err, paramUserID)
+		assert.False(t, ok)
+		_, ok = Get(err, paramName)
+		assert.False(t, ok)
+		_, ok = Get(Wrap(errors.New("origin"), WithParam("user_id", 1.5)), paramUserID)
+		assert.False(t, ok)
+	})
+
+	t.Run("negative unsigned", func(t *testing.T) {
+		key := NewParamKey[uint]("user_id")
+
+		_, ok := Get(Wrap(errors.New("origin"), WithParam("user_id", -1)), key)
+		assert.False(t, ok)
+		_, ok = Get(Wrap(errors.New("origin"), WithParam("user_id", -1.0)), key)
+		assert.False(t, ok)
+
+		id, ok := Get(Wrap(errors.New("origin"), WithParam("user_id", 1)), key)
+		assert.True(t, ok)
+		assert.Equal(t, uint(1), id)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		_, ok := Get(errors.New("origin"), paramUserID)
+		assert.False(t, ok)
+		_, ok = Get(nil, paramUserID)
+		assert.False(t, ok)
+	})
+}
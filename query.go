@@ -0,0 +1,137 @@
+package fail
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// queryNode is a node of a boolean expression parsed by parseQuery.
+// It's shared by TagQuery and ParseMatcher, which give different meanings to the terms.
+type queryNode interface{}
+
+type (
+	queryTerm string
+	queryNot  struct{ node queryNode }
+	queryAnd  struct{ left, right queryNode }
+	queryOr   struct{ left, right queryNode }
+)
+
+// parseQuery parses a boolean expression of terms combined with "!", "&&" and "||"
+// in order of precedence, and grouped with parentheses
+func parseQuery(s string) (queryNode, error) {
+	p := &queryParser{tokens: tokenizeQuery(s)}
+	if len(p.tokens) == 0 {
+		return nil, fmt.Errorf("empty")
+	}
+
+	node, err := p.parseOr()
+	if err == nil && p.pos < len(p.tokens) {
+		err = fmt.Errorf("unexpected %q", p.tokens[p.pos])
+	}
+	return node, err
+}
+
+// tokenizeQuery splits the expression into operators, parentheses and terms
+func tokenizeQuery(s string) []string {
+	var tokens []string
+	for i := 0; i < len(s); {
+		switch {
+		case unicode.IsSpace(rune(s[i])):
+			i++
+		case strings.HasPrefix(s[i:], "&&"), strings.HasPrefix(s[i:], "||"):
+			tokens = append(tokens, s[i:i+2])
+			i += 2
+		case strings.ContainsRune("!()", rune(s[i])):
+			tokens = append(tokens, s[i:i+1])
+			i++
+		default:
+			j := i
+			for j < len(s) && !unicode.IsSpace(rune(s[j])) && !strings.ContainsRune("!()&|", rune(s[j])) {
+				j++
+			}
+			if j == i {
+				// a single "&" or "|"
+				j++
+			}
+			tokens = append(tokens, s[i:j])
+			i = j
+		}
+	}
+	return tokens
+}
+
+// queryParser is a recursive descent parser of boolean expressions
+type queryParser struct {
+	tokens []string
+	pos    int
+}
+
+func (p *queryParser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+func (p *queryParser) parseOr() (queryNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek() == "||" {
+		p.pos++
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = queryOr{left, right}
+	}
+	return left, nil
+}
+
+func (p *queryParser) parseAnd() (queryNode, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek() == "&&" {
+		p.pos++
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = queryAnd{left, right}
+	}
+	return left, nil
+}
+
+func (p *queryParser) parseUnary() (queryNode, error) {
+	switch tok := p.peek(); tok {
+	case "":
+		return nil, fmt.Errorf("unexpected end")
+	case "!":
+		p.pos++
+		node, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return queryNot{node}, nil
+	case "(":
+		p.pos++
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ")" {
+			return nil, fmt.Errorf("missing )")
+		}
+		p.pos++
+		return node, nil
+	case ")", "&&", "||", "&", "|":
+		return nil, fmt.Errorf("unexpected %q", tok)
+	default:
+		p.pos++
+		return queryTerm(tok), nil
+	}
+}
@@ -0,0 +1,174 @@
+package fail
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net"
+	"time"
+)
+
+// ParamAttempts is the key of the param that Retry annotates with the number of attempts
+const ParamAttempts = "attempts"
+
+// WithRetryable annotates an error as retryable
+func WithRetryable() Annotator {
+	return func(err *Error) {
+		err.Retryable = true
+	}
+}
+
+// WithRetryAfter annotates an error as retryable after the duration
+func WithRetryAfter(d time.Duration) Annotator {
+	return func(err *Error) {
+		err.Retryable = true
+		err.RetryAfter = d
+	}
+}
+
+// IsRetryable reports whether the operation that returned the error can be retried.
+//
+// An error is retryable if it's annotated with WithRetryable or WithRetryAfter,
+// has CodeUnavailable or CodeDeadlineExceeded, is context.DeadlineExceeded,
+// or is a net.Error that times out or is temporary.
+// context.Canceled is never retryable since the caller gave up.
+func IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	if failErr := Unwrap(err); failErr != nil {
+		if failErr.Retryable {
+			return true
+		}
+		switch failErr.Code {
+		case CodeUnavailable, CodeDeadlineExceeded:
+			return true
+		}
+		err = failErr.Err
+	}
+
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return netErr.Timeout() || netErr.Temporary()
+	}
+
+	return false
+}
+
+// RetryAfter returns the duration annotated by WithRetryAfter, or 0 if it's not annotated
+func RetryAfter(err error) time.Duration {
+	if failErr := Unwrap(err); failErr != nil {
+		return failErr.RetryAfter
+	}
+	return 0
+}
+
+// RetryPolicy configures Retry.
+// Zero fields are replaced with the ones of DefaultRetryPolicy.
+// Set Multiplier to 1 for a constant interval, and NoJitter for exact intervals.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of calls including the first one
+	MaxAttempts int
+	// InitialInterval is the interval before the first retry
+	InitialInterval time.Duration
+	// MaxInterval is the upper bound of intervals
+	MaxInterval time.Duration
+	// Multiplier is the factor the interval grows by on every retry
+	Multiplier float64
+	// Jitter is the fraction of the interval randomized in both directions, between 0 and 1
+	Jitter float64
+	// NoJitter disables the jitter, so that intervals are deterministic
+	NoJitter bool
+	// IsRetryable reports whether the error should be retried. It's IsRetryable by default.
+	IsRetryable func(error) bool
+}
+
+// DefaultRetryPolicy is the policy used for zero fields of policies passed to Retry
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:     3,
+	InitialInterval: 100 * time.Millisecond,
+	MaxInterval:     10 * time.Second,
+	Multiplier:      2,
+	Jitter:          0.2,
+	IsRetryable:     IsRetryable,
+}
+
+// withDefaults returns a copy of the policy with zero fields filled by DefaultRetryPolicy
+func (p RetryPolicy) withDefaults() RetryPolicy {
+	if p.MaxAttempts == 0 {
+		p.MaxAttempts = DefaultRetryPolicy.MaxAttempts
+	}
+	if p.InitialInterval == 0 {
+		p.InitialInterval = DefaultRetryPolicy.InitialInterval
+	}
+	if p.MaxInterval == 0 {
+		p.MaxInterval = DefaultRetryPolicy.MaxInterval
+	}
+	if p.Multiplier == 0 {
+		p.Multiplier = DefaultRetryPolicy.Multiplier
+	}
+	if p.NoJitter {
+		p.Jitter = 0
+	} else if p.Jitter == 0 {
+		p.Jitter = DefaultRetryPolicy.Jitter
+	}
+	if p.IsRetryable == nil {
+		p.IsRetryable = DefaultRetryPolicy.IsRetryable
+	}
+	return p
+}
+
+// interval returns the interval before the retry following the attempt, starting from 1
+func (p RetryPolicy) interval(attempt int, random float64) time.Duration {
+	d := float64(p.InitialInterval)
+	for i := 1; i < attempt && d < float64(p.MaxInterval); i++ {
+		d *= p.Multiplier
+	}
+	if d > float64(p.MaxInterval) {
+		d = float64(p.MaxInterval)
+	}
+	// random is in [0, 1), so the interval is in [d*(1-jitter), d*(1+jitter))
+	return time.Duration(d * (1 + p.Jitter*(2*random-1)))
+}
+
+// Retry calls fn until it succeeds, returns an error that is not retryable,
+// or it's called policy.MaxAttempts times.
+// It waits for an exponential backoff with jitter between calls,
+// or the duration of WithRetryAfter if it's longer.
+//
+// The final error is wrapped with the number of attempts in the param of ParamAttempts.
+// If ctx is done while waiting, the last error is returned in the same way.
+func Retry(ctx context.Context, policy RetryPolicy, fn func(ctx context.Context) error) error {
+	policy = policy.withDefaults()
+
+	for attempt := 1; ; attempt++ {
+		err := fn(ctx)
+		if err == nil {
+			return nil
+		}
+		if attempt >= policy.MaxAttempts || !policy.IsRetryable(err) {
+			return wrap(err, 0, []Annotator{WithParam(ParamAttempts, attempt)})
+		}
+
+		d := policy.interval(attempt, rand.Float64())
+		if after := RetryAfter(err); after > d {
+			d = after
+		}
+
+		timer := time.NewTimer(d)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return wrap(err, 0, []Annotator{WithParam(ParamAttempts, attempt)})
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,188 @@
+package fail
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type netError struct {
+	timeout, temporary bool
+}
+
+func (e *netError) Error() string   { return "net error" }
+func (e *netError) Timeout() bool   { return e.timeout }
+func (e *netError) Temporary() bool { return e.temporary }
+
+var _ net.Error = (*netError)(nil)
+
+func TestIsRetryable(t *testing.T) {
+	cases := []struct {
+		test string
+		err  error
+		want bool
+	}{
+		{test: "nil", err: nil, want: false},
+		{test: "plain", err: errors.New("origin"), want: false},
+		{test: "fail", err: New("origin"), want: false},
+		{test: "WithRetryable", err: Wrap(New("origin"), WithRetryable()), want: true},
+		{test: "WithRetryAfter", err: Wrap(New("origin"), WithRetryAfter(time.Second)), want: true},
+		{test: "CodeUnavailable", err: Wrap(New("origin"), WithCode(CodeUnavailable)), want: true},
+		{test: "CodeDeadlineExceeded", err: Wrap(New("origin"), WithCode(CodeDeadlineExceeded)), want: true},
+		{test: "CodeNotFound", err: Wrap(New("origin"), WithCode(CodeNotFound)), want: false},
+		{test: "context.DeadlineExceeded", err: Wrap(context.DeadlineExceeded), want: true},
+		{test: "context.Canceled", err: Wrap(context.Canceled), want: false},
+		{test: "net timeout", err: Wrap(&netError{timeout: true}), want: true},
+		{test: "net temporary", err: &netError{temporary: true}, want: true},
+		{test: "net permanent", err: Wrap(&netError{}), want: false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.test, func(t *testing.T) {
+			assert.Equal(t, c.want, IsRetryable(c.err))
+		})
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	assert.Equal(t, time.Second, RetryAfter(Wrap(New("origin"), WithRetryAfter(time.Second))))
+	assert.Equal(t, time.Duration(0), RetryAfter(New("origin")))
+	assert.Equal(t, time.Duration(0), RetryAfter(errors.New("origin")))
+}
+
+func TestRetryPolicy_interval(t *testing.T) {
+	p := RetryPolicy{
+		InitialInterval: 100 * time.Millisecond,
+		MaxInterval:     time.Second,
+		Multiplier:      2,
+		Jitter:          0.5,
+	}
+
+	assert.Equal(t, 100*time.Millisecond, p.interval(1, 0.5))
+	assert.Equal(t, 200*time.Millisecond, p.interval(2, 0.5))
+	assert.Equal(t, 400*time.Millisecond, p.interval(3, 0.5))
+	assert.Equal(t, time.Second, p.interval(5, 0.5))
+	assert.Equal(t, time.Second, p.interval(100, 0.5))
+
+	assert.Equal(t, 50*time.Millisecond, p.interval(1, 0))
+	assert.Equal(t, 150*time.Millisecond, p.interval(1, 1))
+}
+
+func TestRetryPolicy_withDefaults(t *testing.T) {
+	p := RetryPolicy{}.withDefaults()
+	assert.Equal(t, DefaultRetryPolicy.Multiplier, p.Multiplier)
+	assert.Equal(t, DefaultRetryPolicy.Jitter, p.Jitter)
+
+	t.Run("deterministic", func(t *testing.T) {
+		p := RetryPolicy{InitialInterval: 100 * time.Millisecond, Multiplier: 1, Jitter: 0.5, NoJitter: true}.withDefaults()
+		assert.Equal(t, float64(0), p.Jitter)
+		for attempt := 1; attempt <= 3; attempt++ {
+			for _, random := range []float64{0, 0.5, 0.99} {
+				assert.Equal(t, 100*time.Millisecond, p.interval(attempt, random))
+			}
+		}
+	})
+
+	t.Run("exponential without jitter", func(t *testing.T) {
+		p := RetryPolicy{InitialInterval: 100 * time.Millisecond, NoJitter: true}.withDefaults()
+		assert.Equal(t, 100*time.Millisecond, p.interval(1, 0.99))
+		assert.Equal(t, 200*time.Millisecond, p.interval(2, 0))
+		assert.Equal(t, 400*time.Millisecond, p.interval(3, 0.99))
+	})
+}
+
+func TestRetry(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 3, InitialInterval: time.Millisecond}
+
+	t.Run("success", func(t *testing.T) {
+		calls := 0
+		err := Retry(context.Background(), policy, func(ctx context.Context) error {
+			calls++
+			if calls < 2 {
+				return Wrap(New("origin"), WithRetryable())
+			}
+			return nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("exhausted", func(t *testing.T) {
+		calls := 0
+		err := Retry(context.Background(), policy, func(ctx context.Context) error {
+			calls++
+			return Wrap(New("origin"), WithCode(CodeUnavailable))
+		})
+		assert.Equal(t, 3, calls)
+		assert.Equal(t, 3, Unwrap(err).Params[ParamAttempts])
+		assert.Equal(t, CodeUnavailable, Unwrap(err).Code)
+	})
+
+	t.Run("not retryable", func(t *testing.T) {
+		calls := 0
+		err := Retry(context.Background(), policy, func(ctx context.Context) error {
+			calls++
+			return errors.New("origin")
+		})
+		assert.Equal(t, 1, calls)
+		assert.Equal(t, 1, Unwrap(err).Params[ParamAttempts])
+		assert.Equal(t, "TestRetry.func3", Unwrap(err).StackTrace[0].Func)
+	})
+
+	t.Run("custom IsRetryable", func(t *testing.T) {
+		calls := 0
+		p := policy
+		p.IsRetryable = func(err error) bool { return true }
+		Retry(context.Background(), p, func(ctx context.Context) error {
+			calls++
+			return errors.New("origin")
+		})
+		assert.Equal(t, 3, calls)
+	})
+
+	t.Run("RetryAfter", func(t *testing.T) {
+		start := time.Now()
+		Retry(context.Background(), RetryPolicy{MaxAttempts: 2, InitialInterval: time.Millisecond}, func(ctx context.Context) error {
+			return Wrap(New("origin"), WithRetryAfter(50*time.Millisecond))
+		})
+		assert.True(t, time.Since(start) >= 50*time.Millisecond)
+	})
+
+	t.Run("context done", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		calls := 0
+		err := Retry(ctx, RetryPolicy{MaxAttempts: 3, InitialInterval: time.Hour}, func(ctx context.Context) error {
+			calls++
+			cancel()
+			return Wrap(New("origin"), WithRetryable())
+		})
+		assert.Equal(t, 1, calls)
+		assert.Equal(t, "origin", err.Error())
+		assert.Equal(t, 1, Unwrap(err).Params[ParamAttempts])
+	})
+}
+
+func TestError_Retry_Serialization(t *testing.T) {
+	err := &Error{Err: errors.New("origin"), Retryable: true, RetryAfter: 3 * time.Second}
+
+	t.Run("%+v", func(t *testing.T) {
+		assert.Equal(t, "origin\n    retryable: true\n    retry_after: 3s", fmt.Sprintf("%+v", err))
+	})
+
+	t.Run("json", func(t *testing.T) {
+		data, jsonErr := json.Marshal(err)
+		assert.NoError(t, jsonErr)
+		assert.Contains(t, string(data), `"retryable":true,"retry_after":"3s"`)
+
+		var decoded Error
+		assert.NoError(t, json.Unmarshal(data, &decoded))
+		assert.True(t, decoded.Retryable)
+		assert.Equal(t, 3*time.Second, decoded.RetryAfter)
+	})
+}
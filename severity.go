@@ -0,0 +1,105 @@
+package fail
+
+import "fmt"
+
+// Severity is a level of an error to route alerts
+type Severity int
+
+// Severities from lowest to highest.
+// SeverityUnspecified is the zero value, which is treated as SeverityInfo for ignorable errors
+// and as SeverityError for others.
+const (
+	SeverityUnspecified Severity = iota
+	SeverityDebug
+	SeverityInfo
+	SeverityWarning
+	SeverityError
+	SeverityFatal
+)
+
+var severityNames = []string{
+	SeverityUnspecified: "unspecified",
+	SeverityDebug:       "debug",
+	SeverityInfo:        "info",
+	SeverityWarning:     "warning",
+	SeverityError:       "error",
+	SeverityFatal:       "fatal",
+}
+
+// String returns the name of the severity
+func (s Severity) String() string {
+	if s < 0 || int(s) >= len(severityNames) {
+		return fmt.Sprintf("Severity(%d)", int(s))
+	}
+	return severityNames[s]
+}
+
+// ParseSeverity returns the severity of the name
+func ParseSeverity(name string) (Severity, error) {
+	for s, n := range severityNames {
+		if n == name {
+			return Severity(s), nil
+		}
+	}
+	return SeverityUnspecified, fmt.Errorf("unknown severity %q", name)
+}
+
+// MarshalText implements encoding.TextMarshaler
+func (s Severity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler
+func (s *Severity) UnmarshalText(text []byte) error {
+	parsed, err := ParseSeverity(string(text))
+	if err != nil {
+		return err
+	}
+	*s = parsed
+	return nil
+}
+
+// SentryLevel returns the level of Sentry for the severity
+func (s Severity) SentryLevel() string {
+	if s == SeverityUnspecified {
+		return SeverityError.String()
+	}
+	return s.String()
+}
+
+// EffectiveSeverity returns the severity of the error,
+// or the default one derived from Ignorable if it's not specified
+func (e *Error) EffectiveSeverity() Severity {
+	switch {
+	case e.Severity != SeverityUnspecified:
+		return e.Severity
+	case e.Ignorable:
+		return SeverityInfo
+	default:
+		return SeverityError
+	}
+}
+
+// WithSeverity annotates an error with the severity.
+// It only escalates the severity, so that wrapping an error with a lower severity doesn't hide it.
+// Use WithSeverityOverride to lower the severity.
+func WithSeverity(s Severity) Annotator {
+	return func(err *Error) {
+		if s > err.Severity {
+			setSeverity(err, s)
+		}
+	}
+}
+
+// WithSeverityOverride annotates an error with the severity even if it's lower than the current one
+func WithSeverityOverride(s Severity) Annotator {
+	return func(err *Error) {
+		setSeverity(err, s)
+	}
+}
+
+// setSeverity sets the severity, and Ignorable for severities lower than SeverityWarning
+func setSeverity(err *Error, s Severity) {
+	err.Severity = s
+	err.Ignorable = s != SeverityUnspecified && s < SeverityWarning
+}
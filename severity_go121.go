@@ -0,0 +1,23 @@
+//go:build go1.21
+// +build go1.21
+
+package fail
+
+import "log/slog"
+
+// SlogLevel returns the level of log/slog for the severity.
+// SeverityFatal is mapped to a level higher than slog.LevelError.
+func (s Severity) SlogLevel() slog.Level {
+	switch s {
+	case SeverityDebug:
+		return slog.LevelDebug
+	case SeverityInfo:
+		return slog.LevelInfo
+	case SeverityWarning:
+		return slog.LevelWarn
+	case SeverityFatal:
+		return slog.LevelError + 4
+	default:
+		return slog.LevelError
+	}
+}
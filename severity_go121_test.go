@@ -0,0 +1,31 @@
+//go:build go1.21
+// +build go1.21
+
+package fail
+
+import (
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSeverity_SlogLevel(t *testing.T) {
+	cases := []struct {
+		severity Severity
+		want     slog.Level
+	}{
+		{SeverityUnspecified, slog.LevelError},
+		{SeverityDebug, slog.LevelDebug},
+		{SeverityInfo, slog.LevelInfo},
+		{SeverityWarning, slog.LevelWarn},
+		{SeverityError, slog.LevelError},
+		{SeverityFatal, slog.LevelError + 4},
+	}
+
+	for _, c := range cases {
+		t.Run(c.severity.String(), func(t *testing.T) {
+			assert.Equal(t, c.want, c.severity.SlogLevel())
+		})
+	}
+}
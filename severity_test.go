@@ -0,0 +1,120 @@
+package fail
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSeverity_String(t *testing.T) {
+	assert.Equal(t, "unspecified", SeverityUnspecified.String())
+	assert.Equal(t, "warning", SeverityWarning.String())
+	assert.Equal(t, "fatal", SeverityFatal.String())
+	assert.Equal(t, "Severity(10)", Severity(10).String())
+}
+
+func TestParseSeverity(t *testing.T) {
+	for s := SeverityUnspecified; s <= SeverityFatal; s++ {
+		parsed, err := ParseSeverity(s.String())
+		assert.NoError(t, err)
+		assert.Equal(t, s, parsed)
+	}
+
+	_, err := ParseSeverity("critical")
+	assert.Error(t, err)
+}
+
+func TestSeverity_SentryLevel(t *testing.T) {
+	assert.Equal(t, "error", SeverityUnspecified.SentryLevel())
+	assert.Equal(t, "debug", SeverityDebug.SentryLevel())
+	assert.Equal(t, "warning", SeverityWarning.SentryLevel())
+	assert.Equal(t, "fatal", SeverityFatal.SentryLevel())
+}
+
+func TestWithSeverity(t *testing.T) {
+	cases := []struct {
+		test          string
+		annotators    []Annotator
+		wantSeverity  Severity
+		wantIgnorable bool
+	}{
+		{
+			test:         "unspecified",
+			wantSeverity: SeverityUnspecified,
+		},
+		{
+			test:         "set",
+			annotators:   []Annotator{WithSeverity(SeverityWarning)},
+			wantSeverity: SeverityWarning,
+		},
+		{
+			test:         "escalate",
+			annotators:   []Annotator{WithSeverity(SeverityWarning), WithSeverity(SeverityFatal)},
+			wantSeverity: SeverityFatal,
+		},
+		{
+			test:         "not lowered",
+			annotators:   []Annotator{WithSeverity(SeverityError), WithSeverity(SeverityInfo)},
+			wantSeverity: SeverityError,
+		},
+		{
+			test:          "override",
+			annotators:    []Annotator{WithSeverity(SeverityError), WithSeverityOverride(SeverityDebug)},
+			wantSeverity:  SeverityDebug,
+			wantIgnorable: true,
+		},
+		{
+			test:          "ignorable",
+			annotators:    []Annotator{WithSeverity(SeverityError), WithIgnorable()},
+			wantSeverity:  SeverityInfo,
+			wantIgnorable: true,
+		},
+		{
+			test:         "escalate ignorable",
+			annotators:   []Annotator{WithIgnorable(), WithSeverity(SeverityWarning)},
+			wantSeverity: SeverityWarning,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.test, func(t *testing.T) {
+			err := New("origin")
+			for _, f := range c.annotators {
+				err = Wrap(err, f)
+			}
+
+			failErr := Unwrap(err)
+			assert.Equal(t, c.wantSeverity, failErr.Severity)
+			assert.Equal(t, c.wantIgnorable, failErr.Ignorable)
+		})
+	}
+}
+
+func TestError_EffectiveSeverity(t *testing.T) {
+	assert.Equal(t, SeverityError, (&Error{}).EffectiveSeverity())
+	assert.Equal(t, SeverityInfo, (&Error{Ignorable: true}).EffectiveSeverity())
+	assert.Equal(t, SeverityWarning, (&Error{Severity: SeverityWarning}).EffectiveSeverity())
+}
+
+func TestError_Severity_Serialization(t *testing.T) {
+	err := &Error{Err: errors.New("origin"), Severity: SeverityWarning}
+
+	t.Run("%+v", func(t *testing.T) {
+		assert.Equal(t, "origin\n    severity: warning", fmt.Sprintf("%+v", err))
+	})
+
+	t.Run("json", func(t *testing.T) {
+		data, jsonErr := json.Marshal(err)
+		assert.NoError(t, jsonErr)
+		assert.Contains(t, string(data), `"severity":"warning"`)
+
+		var decoded Error
+		assert.NoError(t, json.Unmarshal(data, &decoded))
+		assert.Equal(t, SeverityWarning, decoded.Severity)
+
+		assert.Error(t, json.Unmarshal([]byte(`{"severity":"critical"}`), &decoded))
+	})
+}
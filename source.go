@@ -0,0 +1,235 @@
+package fail
+
+import (
+	"bufio"
+	"io"
+	"io/fs"
+	"os"
+	"runtime/debug"
+	"strings"
+	"sync"
+)
+
+// SourceContext is lines of source code around the line of a frame
+type SourceContext struct {
+	// StartLine is the line number of the first line, which is the first line of PreContext if any
+	StartLine int64
+	// PreContext is lines before the line of the frame
+	PreContext []string
+	// ContextLine is the line of the frame
+	ContextLine string
+	// PostContext is lines after the line of the frame
+	PostContext []string
+}
+
+// SourceLine is a numbered line of SourceContext
+type SourceLine struct {
+	Number int64
+	Text   string
+	// Current represents whether the line is the line of the frame
+	Current bool
+}
+
+// Lines returns all lines of the context with their line numbers
+func (c *SourceContext) Lines() []SourceLine {
+	lines := make([]SourceLine, 0, len(c.PreContext)+1+len(c.PostContext))
+	n := c.StartLine
+	for _, text := range c.PreContext {
+		lines = append(lines, SourceLine{Number: n, Text: text})
+		n++
+	}
+	lines = append(lines, SourceLine{Number: n, Text: c.ContextLine, Current: true})
+	n++
+	for _, text := range c.PostContext {
+		lines = append(lines, SourceLine{Number: n, Text: text})
+		n++
+	}
+	return lines
+}
+
+// SourceOpener opens a source file by its path
+type SourceOpener func(name string) (io.ReadCloser, error)
+
+// SourceLoader loads source code around frames.
+// Files are read once and cached, including the ones that don't exist.
+type SourceLoader struct {
+	lines int
+	open  SourceOpener
+
+	mu      sync.Mutex
+	files   map[string][]string
+	modules []string
+}
+
+// NewSourceLoader creates a SourceLoader that loads the number of lines before and after the line of each frame.
+// It reads files from the local file system if open is nil.
+func NewSourceLoader(lines int, open SourceOpener) *SourceLoader {
+	if open == nil {
+		open = func(name string) (io.ReadCloser, error) {
+			return os.Open(name)
+		}
+	}
+	return &SourceLoader{
+		lines: lines,
+		open:  open,
+		files: map[string][]string{},
+	}
+}
+
+// WithModules adds module paths or directories whose files are searched relative to the root of the loader,
+// so that "github.com/example/app/main.go" is found as "main.go" with "github.com/example/app".
+// The main module of the binary, the module of go.mod in the working directory and the working directory
+// are searched by default. It returns the loader itself.
+func (l *SourceLoader) WithModules(modules ...string) *SourceLoader {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, m := range modules {
+		if m = strings.TrimRight(m, "/"); m != "" {
+			l.modules = append(l.modules, m)
+		}
+	}
+	l.files = map[string][]string{}
+	return l
+}
+
+// Load returns the source code around the line of the frame.
+// The file is searched as is, and then relative to the root of the loader
+// with the path of a module in WithModules removed.
+// It returns false if the file or the line is not found.
+func (l *SourceLoader) Load(f Frame) (*SourceContext, bool) {
+	lines := l.readFile(f.File)
+	if f.Line < 1 || int(f.Line) > len(lines) {
+		return nil, false
+	}
+
+	i := int(f.Line) - 1
+	from, to := i-l.lines, i+l.lines+1
+	if from < 0 {
+		from = 0
+	}
+	if to > len(lines) {
+		to = len(lines)
+	}
+
+	return &SourceContext{
+		StartLine:   int64(from) + 1,
+		PreContext:  lines[from:i],
+		ContextLine: lines[i],
+		PostContext: lines[i+1 : to],
+	}, true
+}
+
+// readFile returns the lines of the file from the cache, or reads it
+func (l *SourceLoader) readFile(file string) []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if lines, ok := l.files[file]; ok {
+		return lines
+	}
+
+	var lines []string
+	for _, name := range l.candidates(file) {
+		if r, err := l.open(name); err == nil {
+			lines = readLines(r)
+			r.Close()
+			break
+		}
+	}
+
+	l.files[file] = lines
+	return lines
+}
+
+// candidates returns the names to search the file by.
+// Only the paths of known modules are removed, so that a file of another package,
+// such as "net/http/server.go", is never found as an unrelated "server.go".
+func (l *SourceLoader) candidates(file string) []string {
+	names := []string{file}
+	modules := append(append([]string{}, l.modules...), defaultSourceModules()...)
+	for _, m := range modules {
+		if strings.HasPrefix(file, m+"/") {
+			names = append(names, file[len(m)+1:])
+		} else if i := strings.Index(file, "/"+m+"/"); i >= 0 {
+			names = append(names, file[i+len(m)+2:])
+		}
+	}
+	return names
+}
+
+var (
+	sourceModulesOnce sync.Once
+	sourceModules     []string
+)
+
+// defaultSourceModules returns the path of the main module, the module path of go.mod in the working directory,
+// and the working directory
+func defaultSourceModules() []string {
+	sourceModulesOnce.Do(func() {
+		if info, ok := debug.ReadBuildInfo(); ok && info.Main.Path != "" && info.Main.Path != "command-line-arguments" {
+			sourceModules = append(sourceModules, info.Main.Path)
+		}
+		if m := readModulePath("go.mod"); m != "" {
+			sourceModules = append(sourceModules, m)
+		}
+		if wd, err := os.Getwd(); err == nil && wd != "/" {
+			sourceModules = append(sourceModules, strings.Trim(wd, "/"))
+		}
+	})
+	return sourceModules
+}
+
+// readModulePath returns the module path declared in the go.mod file, or "" if it's not found
+func readModulePath(name string) string {
+	f, err := os.Open(name)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	for _, line := range readLines(f) {
+		if fields := strings.Fields(line); len(fields) >= 2 && fields[0] == "module" {
+			return strings.Trim(fields[1], `"`)
+		}
+	}
+	return ""
+}
+
+// readLines reads all lines from the reader
+func readLines(r io.Reader) []string {
+	var lines []string
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), 1024*1024)
+	for sc.Scan() {
+		lines = append(lines, sc.Text())
+	}
+	return lines
+}
+
+var (
+	sourceLoaderMu sync.RWMutex
+	sourceLoader   = NewSourceLoader(2, nil)
+)
+
+// SetSourceLoader sets the SourceLoader used by the "%+#v" format.
+// Source code is not printed if it's nil.
+func SetSourceLoader(l *SourceLoader) {
+	sourceLoaderMu.Lock()
+	defer sourceLoaderMu.Unlock()
+	sourceLoader = l
+}
+
+// getSourceLoader returns the SourceLoader set by SetSourceLoader
+func getSourceLoader() *SourceLoader {
+	sourceLoaderMu.RLock()
+	defer sourceLoaderMu.RUnlock()
+	return sourceLoader
+}
+
+// NewSourceLoaderFS creates a SourceLoader that reads files from the file system, such as embed.FS.
+// Leading slashes of paths are removed since fs.FS doesn't accept rooted paths.
+func NewSourceLoaderFS(lines int, fsys fs.FS) *SourceLoader {
+	return NewSourceLoader(lines, func(name string) (io.ReadCloser, error) {
+		return fsys.Open(strings.TrimLeft(name, "/"))
+	})
+}
@@ -0,0 +1,213 @@
+package fail
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testSource = `package main
+
+func f1() error {
+	return fail.New("origin")
+}
+
+func main() {
+	f1()
+}`
+
+func newTestSourceLoader(lines int, files map[string]string, opened *[]string) *SourceLoader {
+	return NewSourceLoader(lines, func(name string) (io.ReadCloser, error) {
+		if opened != nil {
+			*opened = append(*opened, name)
+		}
+		src, ok := files[name]
+		if !ok {
+			return nil, os.ErrNotExist
+		}
+		return ioutil.NopCloser(strings.NewReader(src)), nil
+	})
+}
+
+func TestSourceLoader_Load(t *testing.T) {
+	loader := newTestSourceLoader(2, map[string]string{"app/main.go": testSource, "server.go": testSource}, nil).WithModules("github.com/example/")
+
+	cases := []struct {
+		test  string
+		frame Frame
+		want  *SourceContext
+	}{
+		{
+			test:  "middle",
+			frame: Frame{File: "app/main.go", Line: 4},
+			want: &SourceContext{
+				StartLine:   2,
+				PreContext:  []string{"", "func f1() error {"},
+				ContextLine: `	return fail.New("origin")`,
+				PostContext: []string{"}", ""},
+			},
+		},
+		{
+			test:  "first line",
+			frame: Frame{File: "app/main.go", Line: 1},
+			want: &SourceContext{
+				StartLine:   1,
+				PreContext:  []string{},
+				ContextLine: "package main",
+				PostContext: []string{"", "func f1() error {"},
+			},
+		},
+		{
+			test:  "last line",
+			frame: Frame{File: "app/main.go", Line: 9},
+			want: &SourceContext{
+				StartLine:   7,
+				PreContext:  []string{"func main() {", "\tf1()"},
+				ContextLine: "}",
+				PostContext: []string{},
+			},
+		},
+		{
+			test:  "leading directories",
+			frame: Frame{File: "github.com/example/app/main.go", Line: 8},
+			want: &SourceContext{
+				StartLine:   6,
+				PreContext:  []string{"", "func main() {"},
+				ContextLine: "\tf1()",
+				PostContext: []string{"}"},
+			},
+		},
+		{
+			test:  "gopath",
+			frame: Frame{File: "/home/user/go/src/github.com/example/app/main.go", Line: 8},
+			want: &SourceContext{
+				StartLine:   6,
+				PreContext:  []string{"", "func main() {"},
+				ContextLine: "\tf1()",
+				PostContext: []string{"}"},
+			},
+		},
+		{
+			test:  "other package",
+			frame: Frame{File: "net/http/server.go", Line: 1},
+		},
+		{
+			test:  "other module",
+			frame: Frame{File: "example.com/app/main.go", Line: 1},
+		},
+		{
+			test:  "line out of range",
+			frame: Frame{File: "app/main.go", Line: 10},
+		},
+		{
+			test:  "file not found",
+			frame: Frame{File: "app/other.go", Line: 1},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.test, func(t *testing.T) {
+			src, ok := loader.Load(c.frame)
+			assert.Equal(t, c.want != nil, ok)
+			assert.Equal(t, c.want, src)
+		})
+	}
+
+	t.Run("cache", func(t *testing.T) {
+		var opened []string
+		loader := newTestSourceLoader(1, map[string]string{"main.go": testSource}, &opened).WithModules("app")
+
+		for i := 0; i < 2; i++ {
+			loader.Load(Frame{File: "app/main.go", Line: 4})
+			loader.Load(Frame{File: "app/other.go", Line: 4})
+		}
+		assert.Equal(t, []string{"app/main.go", "main.go", "app/other.go", "other.go"}, opened)
+	})
+}
+
+func TestSourceContext_Lines(t *testing.T) {
+	src := &SourceContext{StartLine: 3, PreContext: []string{"a"}, ContextLine: "b", PostContext: []string{"c", "d"}}
+	assert.Equal(t, []SourceLine{
+		{Number: 3, Text: "a"},
+		{Number: 4, Text: "b", Current: true},
+		{Number: 5, Text: "c"},
+		{Number: 6, Text: "d"},
+	}, src.Lines())
+}
+
+func TestError_Format_Source(t *testing.T) {
+	defer SetSourceLoader(getSourceLoader())
+	SetSourceLoader(newTestSourceLoader(1, map[string]string{"main.go": testSource}, nil))
+
+	err := &Error{
+		Err: errors.New("origin"),
+		StackTrace: StackTrace{
+			{Func: "f1", File: "main.go", Line: 4},
+			{Func: "main", File: "main.go", Line: 8},
+			{Func: "other", File: "other.go", Line: 1},
+		},
+	}
+
+	assert.Equal(t, `origin
+f1
+	main.go:4
+	      3 | func f1() error {
+	>     4 | 	return fail.New("origin")
+	      5 | }
+main
+	main.go:8
+	      7 | func main() {
+	>     8 | 	f1()
+	      9 | }
+other
+	other.go:1`, fmt.Sprintf("%+#v", err))
+
+	t.Run("without loader", func(t *testing.T) {
+		SetSourceLoader(nil)
+		assert.Equal(t, fmt.Sprintf("%+v", err), fmt.Sprintf("%+#v", err))
+	})
+}
+
+func TestNewSourceLoaderFS(t *testing.T) {
+	loader := NewSourceLoaderFS(1, fstest.MapFS{
+		"main.go": {Data: []byte(testSource)},
+	}).WithModules("/home/user/app")
+
+	src, ok := loader.Load(Frame{File: "/home/user/app/main.go", Line: 8})
+	assert.True(t, ok)
+	assert.Equal(t, &SourceContext{
+		StartLine:   7,
+		PreContext:  []string{"func main() {"},
+		ContextLine: "\tf1()",
+		PostContext: []string{"}"},
+	}, src)
+}
+
+func TestSourceLoader_Load_Decoy(t *testing.T) {
+	dir := t.TempDir()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile("server.go", []byte(testSource), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	loader := NewSourceLoader(1, nil)
+	_, ok := loader.Load(Frame{File: "net/http/server.go", Line: 4})
+	assert.False(t, ok, "should not load an unrelated file in the working directory")
+
+	_, ok = loader.Load(Frame{File: dir + "/server.go", Line: 4})
+	assert.True(t, ok)
+}
@@ -1,6 +1,7 @@
 package fail
 
 import (
+	"fmt"
 	"runtime"
 	"strings"
 )
@@ -18,6 +19,77 @@ type Frame struct {
 	Func string
 	File string
 	Line int64
+	// Remote represents whether the frame is of another service, rebuilt from an error propagated over the wire
+	Remote bool
+	// Origin is the service and the host where the remote frame was recorded
+	Origin Origin
+}
+
+// InApp reports whether the file or the function of the frame has any of the prefixes,
+// that is, the frame belongs to the application. Every frame belongs to it if no prefixes are given.
+func (f Frame) InApp(prefixes ...string) bool {
+	if len(prefixes) == 0 {
+		return true
+	}
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(f.File, prefix) || strings.HasPrefix(f.Func, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Origin represents where remote frames were recorded
+type Origin struct {
+	Service string
+	Host    string
+}
+
+// String returns the service name, or the host if the service is unknown
+func (o Origin) String() string {
+	if o.Service != "" {
+		return o.Service
+	}
+	return o.Host
+}
+
+// Local returns the frames recorded in the current process
+func (st StackTrace) Local() StackTrace {
+	var frames StackTrace
+	for _, f := range st {
+		if !f.Remote {
+			frames = append(frames, f)
+		}
+	}
+	return frames
+}
+
+// Remote returns the frames recorded in other services
+func (st StackTrace) Remote() StackTrace {
+	var frames StackTrace
+	for _, f := range st {
+		if f.Remote {
+			frames = append(frames, f)
+		}
+	}
+	return frames
+}
+
+// Separator returns the line printed before the i-th frame if its origin differs from the previous frame,
+// such as "--- remote (users) ---" and "--- local ---", or "" otherwise
+func (st StackTrace) Separator(i int) string {
+	f := st[i]
+	if i == 0 || st[i-1].Remote != f.Remote || st[i-1].Origin != f.Origin {
+		switch {
+		case !f.Remote && i > 0:
+			return "--- local ---"
+		case f.Remote && f.Origin.String() != "":
+			return fmt.Sprintf("--- remote (%s) ---", f.Origin)
+		case f.Remote:
+			return "--- remote ---"
+		}
+	}
+	return ""
 }
 
 // newFrameFromRuntimeFrame creates Frame from the specified runtime.Frame
@@ -108,7 +180,8 @@ func trimGOPATH(name, file string) string {
 	return file
 }
 
-// mergeStackTraces merges two stack traces
+// mergeStackTraces merges two stack traces.
+// Frames are compared with their origins, so frames of different origins are never merged.
 func mergeStackTraces(inner StackTrace, outer StackTrace) StackTrace {
 	innerLen := len(inner)
 	outerLen := len(outer)
@@ -133,6 +133,58 @@ func TestMergeStackTraces(t *testing.T) {
 
 		assert.Equal(t, result, mergeStackTraces(inner, outer))
 	})
+
+	t.Run("different origins", func(t *testing.T) {
+		inner := StackTrace{
+			{Func: "find", File: "users.go", Line: 10, Remote: true, Origin: Origin{Service: "users"}},
+			{Func: "f2", File: "main.go", Line: 161, Remote: true, Origin: Origin{Service: "users"}},
+			{Func: "main", File: "main.go", Line: 179, Remote: true, Origin: Origin{Service: "users"}},
+		}
+		outer := StackTrace{
+			{Func: "f2", File: "main.go", Line: 161},
+			{Func: "main", File: "main.go", Line: 179},
+		}
+		result := append(append(StackTrace{}, inner...), outer...)
+
+		assert.Equal(t, result, mergeStackTraces(inner, outer))
+	})
+}
+
+func TestStackTrace_Origins(t *testing.T) {
+	st := StackTrace{
+		{Func: "find", File: "users.go", Line: 10, Remote: true, Origin: Origin{Service: "users", Host: "users-1"}},
+		{Func: "get", File: "users.go", Line: 20, Remote: true, Origin: Origin{Service: "users", Host: "users-1"}},
+		{Func: "call", File: "gateway.go", Line: 30, Remote: true, Origin: Origin{Host: "gateway-1"}},
+		{Func: "proxy", File: "proxy.go", Line: 40, Remote: true},
+		{Func: "f1", File: "main.go", Line: 157},
+		{Func: "main", File: "main.go", Line: 179},
+	}
+
+	t.Run("Local", func(t *testing.T) {
+		assert.Equal(t, st[4:], st.Local())
+		assert.Nil(t, st[:4].Local())
+	})
+
+	t.Run("Remote", func(t *testing.T) {
+		assert.Equal(t, st[:4], st.Remote())
+		assert.Nil(t, st[4:].Remote())
+	})
+
+	t.Run("Separator", func(t *testing.T) {
+		var separators []string
+		for i := range st {
+			separators = append(separators, st.Separator(i))
+		}
+		assert.Equal(t, []string{
+			"--- remote (users) ---",
+			"",
+			"--- remote (gateway-1) ---",
+			"--- remote ---",
+			"--- local ---",
+			"",
+		}, separators)
+		assert.Equal(t, "", st[4:].Separator(0))
+	})
 }
 
 func TestReduceStackTraces(t *testing.T) {
@@ -167,3 +219,12 @@ func TestReduceStackTraces(t *testing.T) {
 
 	assert.Equal(t, result, reduceStackTraces(input))
 }
+
+func TestFrame_InApp(t *testing.T) {
+	f := Frame{Func: "github.com/example/app.FindUser", File: "/home/user/app/user.go", Line: 4}
+
+	assert.True(t, f.InApp())
+	assert.True(t, f.InApp("/home/user/app/"))
+	assert.True(t, f.InApp("net/http", "github.com/example/app"))
+	assert.False(t, f.InApp("net/http", "/home/user/lib/"))
+}
@@ -0,0 +1,72 @@
+package fail
+
+import "fmt"
+
+// TagQuery is a boolean expression of tags, such as "db && !timeout" and "(http || grpc) && team:*".
+//
+// A term is a tag that matches if the tags have it, and "key:*" matches any tag namespaced by the key.
+// Terms are combined with "!", "&&" and "||" in order of precedence, and grouped with parentheses.
+type TagQuery struct {
+	src  string
+	node queryNode
+}
+
+// ParseTagQuery parses the expression of a TagQuery
+func ParseTagQuery(s string) (*TagQuery, error) {
+	node, err := parseQuery(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid tag query %q: %v", s, err)
+	}
+	return &TagQuery{src: s, node: node}, nil
+}
+
+// MustParseTagQuery is like ParseTagQuery, but it panics if the expression is invalid
+func MustParseTagQuery(s string) *TagQuery {
+	q, err := ParseTagQuery(s)
+	if err != nil {
+		panic(err)
+	}
+	return q
+}
+
+// Match reports whether the tags match the query
+func (q *TagQuery) Match(tags []string) bool {
+	return matchTags(q.node, TagSet(tags))
+}
+
+// MatchError reports whether the tags of the error match the query
+func (q *TagQuery) MatchError(err error) bool {
+	failErr := Unwrap(err)
+	if failErr == nil {
+		return q.Match(nil)
+	}
+	return q.Match(failErr.Tags)
+}
+
+// String returns the expression of the query
+func (q *TagQuery) String() string {
+	return q.src
+}
+
+// matchTags evaluates the node with the tags
+func matchTags(node queryNode, tags TagSet) bool {
+	switch n := node.(type) {
+	case queryNot:
+		return !matchTags(n.node, tags)
+	case queryAnd:
+		return matchTags(n.left, tags) && matchTags(n.right, tags)
+	case queryOr:
+		return matchTags(n.left, tags) || matchTags(n.right, tags)
+	case queryTerm:
+		return matchTag(string(n), tags)
+	}
+	return false
+}
+
+// matchTag reports whether the tags have the tag, or any tag in the namespace for "key:*"
+func matchTag(tag string, tags TagSet) bool {
+	if key, value, ok := SplitTag(tag); ok && value == "*" {
+		return len(tags.Values(key)) > 0
+	}
+	return tags.Has(tag)
+}
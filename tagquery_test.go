@@ -0,0 +1,70 @@
+package fail
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTagQuery_Match(t *testing.T) {
+	tags := []string{"db", "timeout", "team:payment"}
+
+	cases := []struct {
+		query string
+		want  bool
+	}{
+		{query: "db", want: true},
+		{query: "http", want: false},
+		{query: "!http", want: true},
+		{query: "db && timeout", want: true},
+		{query: "db && !timeout", want: false},
+		{query: "http || timeout", want: true},
+		{query: "http || db && cache", want: false},
+		{query: "(http || db) && !cache", want: true},
+		{query: "!(db && timeout)", want: false},
+		{query: "!!db", want: true},
+		{query: "team:*", want: true},
+		{query: "region:*", want: false},
+		{query: "team:payment && !team:billing", want: true},
+		{query: "db&&timeout", want: true},
+	}
+
+	for _, c := range cases {
+		q, err := ParseTagQuery(c.query)
+		if assert.NoError(t, err, c.query) {
+			assert.Equal(t, c.want, q.Match(tags), c.query)
+			assert.Equal(t, c.query, q.String())
+		}
+	}
+}
+
+func TestParseTagQuery_Invalid(t *testing.T) {
+	cases := []string{
+		"",
+		"  ",
+		"db ||",
+		"&& db",
+		"db timeout",
+		"(db",
+		"db)",
+		"db & timeout",
+		"!",
+	}
+
+	for _, s := range cases {
+		_, err := ParseTagQuery(s)
+		assert.Error(t, err, s)
+	}
+
+	assert.Panics(t, func() { MustParseTagQuery("(") })
+}
+
+func TestTagQuery_MatchError(t *testing.T) {
+	q := MustParseTagQuery("db && !retry")
+
+	assert.True(t, q.MatchError(Wrap(errors.New("origin"), WithTags("db"))))
+	assert.False(t, q.MatchError(Wrap(errors.New("origin"), WithTags("db", "retry"))))
+	assert.False(t, q.MatchError(errors.New("origin")))
+	assert.True(t, MustParseTagQuery("!db").MatchError(errors.New("origin")))
+}
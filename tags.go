@@ -0,0 +1,85 @@
+package fail
+
+import "strings"
+
+// tagSeparator separates the key and the value of a namespaced tag
+const tagSeparator = ":"
+
+// TagSet is tags without duplicates in order of addition.
+// Tags can be namespaced as "key:value".
+type TagSet []string
+
+// NewTagSet creates a TagSet of the tags without duplicates
+func NewTagSet(tags ...string) TagSet {
+	return TagSet(nil).Add(tags...)
+}
+
+// Add returns a TagSet that has the tags appended if they are not in the set yet.
+// The set itself is not modified.
+func (s TagSet) Add(tags ...string) TagSet {
+	out := s[:len(s):len(s)]
+	for _, tag := range tags {
+		if !out.Has(tag) {
+			out = append(out, tag)
+		}
+	}
+	return out
+}
+
+// Has reports whether the set has the tag
+func (s TagSet) Has(tag string) bool {
+	for _, t := range s {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// HasAny reports whether the set has any of the tags
+func (s TagSet) HasAny(tags ...string) bool {
+	for _, tag := range tags {
+		if s.Has(tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// Values returns the values of the tags namespaced by the key in order
+func (s TagSet) Values(key string) []string {
+	var values []string
+	for _, t := range s {
+		if k, v, ok := SplitTag(t); ok && k == key {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// NamespacedTag returns the tag of the value namespaced by the key, "key:value"
+func NamespacedTag(key, value string) string {
+	return key + tagSeparator + value
+}
+
+// SplitTag splits a namespaced tag into the key and the value.
+// It returns false if the tag is not namespaced.
+func SplitTag(tag string) (key, value string, ok bool) {
+	i := strings.Index(tag, tagSeparator)
+	if i < 0 {
+		return "", "", false
+	}
+	return tag[:i], tag[i+len(tagSeparator):], true
+}
+
+// HasTag reports whether the error has the tag
+func HasTag(err error, tag string) bool {
+	failErr := Unwrap(err)
+	return failErr != nil && TagSet(failErr.Tags).Has(tag)
+}
+
+// HasAnyTag reports whether the error has any of the tags
+func HasAnyTag(err error, tags ...string) bool {
+	failErr := Unwrap(err)
+	return failErr != nil && TagSet(failErr.Tags).HasAny(tags...)
+}
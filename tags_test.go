@@ -0,0 +1,72 @@
+package fail
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTagSet(t *testing.T) {
+	t.Run("dedup", func(t *testing.T) {
+		s := NewTagSet("db", "user", "db")
+		assert.Equal(t, TagSet{"db", "user"}, s)
+		assert.Equal(t, TagSet{"db", "user", "http"}, s.Add("user", "http", "http"))
+		assert.Equal(t, TagSet{"db", "user"}, s)
+	})
+
+	t.Run("copy on add", func(t *testing.T) {
+		s := make(TagSet, 1, 4)
+		s[0] = "db"
+		s1 := s.Add("user")
+		s2 := s.Add("http")
+		assert.Equal(t, TagSet{"db", "user"}, s1)
+		assert.Equal(t, TagSet{"db", "http"}, s2)
+	})
+
+	t.Run("has", func(t *testing.T) {
+		s := NewTagSet("db", "team:payment")
+		assert.True(t, s.Has("db"))
+		assert.False(t, s.Has("http"))
+		assert.True(t, s.HasAny("http", "team:payment"))
+		assert.False(t, s.HasAny("http", "team"))
+		assert.False(t, s.HasAny())
+	})
+
+	t.Run("namespaces", func(t *testing.T) {
+		s := NewTagSet(NamespacedTag("team", "payment"), "db", "team:billing", "region:us")
+		assert.Equal(t, []string{"payment", "billing"}, s.Values("team"))
+		assert.Nil(t, s.Values("db"))
+	})
+}
+
+func TestSplitTag(t *testing.T) {
+	cases := []struct {
+		tag        string
+		key, value string
+		ok         bool
+	}{
+		{tag: "db"},
+		{tag: "team:payment", key: "team", value: "payment", ok: true},
+		{tag: "url:http://example.com", key: "url", value: "http://example.com", ok: true},
+		{tag: ":", ok: true},
+	}
+
+	for _, c := range cases {
+		key, value, ok := SplitTag(c.tag)
+		assert.Equal(t, c.key, key, c.tag)
+		assert.Equal(t, c.value, value, c.tag)
+		assert.Equal(t, c.ok, ok, c.tag)
+	}
+}
+
+func TestHasTag(t *testing.T) {
+	err := Wrap(errors.New("origin"), WithTags("db", "team:payment"))
+
+	assert.True(t, HasTag(err, "db"))
+	assert.False(t, HasTag(err, "http"))
+	assert.True(t, HasAnyTag(err, "http", "team:payment"))
+	assert.False(t, HasAnyTag(err, "http"))
+	assert.False(t, HasTag(errors.New("origin"), "db"))
+	assert.False(t, HasAnyTag(nil, "db"))
+}
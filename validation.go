@@ -0,0 +1,134 @@
+package fail
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// FieldViolation is a violation of a constraint on a field of an input
+type FieldViolation struct {
+	// Field is the path of the field, such as "user.emails[0]"
+	Field string `json:"field"`
+	// Constraint is the name of the violated constraint, such as "required"
+	Constraint string `json:"constraint"`
+	// Message is a description of the violation
+	Message string `json:"message"`
+}
+
+// ValidationError is the root error of an error built by Validation
+type ValidationError struct {
+	Violations []FieldViolation
+}
+
+// Error implements error interface.
+// It returns the violations joined with "; ".
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		msgs[i] = v.Field + ": " + v.Message
+	}
+	return "invalid argument: " + strings.Join(msgs, "; ")
+}
+
+// Validation collects violations of fields of an input, and builds an error of them.
+//
+//	v := fail.NewValidation()
+//	v.Check(req.Name != "", "name", "required", "must not be empty")
+//	addr := v.In("address")
+//	addr.Check(len(req.Address.Zip) == 7, "zip", "len", "must be 7 digits")
+//	if err := v.Err(); err != nil {
+//		return err
+//	}
+type Validation struct {
+	prefix     string
+	violations *[]FieldViolation
+}
+
+// NewValidation creates an empty Validation
+func NewValidation() *Validation {
+	return &Validation{violations: new([]FieldViolation)}
+}
+
+// Add adds a violation of the field
+func (v *Validation) Add(field, constraint, message string) *Validation {
+	*v.violations = append(*v.violations, FieldViolation{
+		Field:      joinFieldPath(v.prefix, field),
+		Constraint: constraint,
+		Message:    message,
+	})
+	return v
+}
+
+// Addf adds a violation of the field with the formatted message
+func (v *Validation) Addf(field, constraint, format string, args ...interface{}) *Validation {
+	return v.Add(field, constraint, fmt.Sprintf(format, args...))
+}
+
+// Check adds a violation of the field if ok is false
+func (v *Validation) Check(ok bool, field, constraint, message string) *Validation {
+	if !ok {
+		v.Add(field, constraint, message)
+	}
+	return v
+}
+
+// In returns a Validation for the nested field, that adds violations to v with the path of the field prefixed
+func (v *Validation) In(field string) *Validation {
+	return &Validation{
+		prefix:     joinFieldPath(v.prefix, field),
+		violations: v.violations,
+	}
+}
+
+// Violations returns the violations added so far
+func (v *Validation) Violations() []FieldViolation {
+	return *v.violations
+}
+
+// Err returns an error of the violations with CodeInvalidArgument, or nil if there are no violations.
+// It also records the stack trace at the point it was called, and applies the annotators.
+func (v *Validation) Err(annotators ...Annotator) error {
+	if len(*v.violations) == 0 {
+		return nil
+	}
+
+	violations := make([]FieldViolation, len(*v.violations))
+	copy(violations, *v.violations)
+
+	err := &Error{Err: &ValidationError{Violations: violations}, CreatedAt: now()}
+	withStackTrace(0)(err)
+	runHooks(&createHooks, err)
+	WithCode(CodeInvalidArgument)(err)
+	for _, f := range annotators {
+		f(err)
+	}
+	return err
+}
+
+// FieldViolations returns the violations of the error built by Validation, or nil if it's not
+func FieldViolations(err error) []FieldViolation {
+	if failErr := Unwrap(err); failErr != nil {
+		err = failErr.Err
+	}
+	var verr *ValidationError
+	if errors.As(err, &verr) {
+		return verr.Violations
+	}
+	return nil
+}
+
+// joinFieldPath joins a path of a field and a nested field.
+// Indexes such as "[0]" are joined without dots.
+func joinFieldPath(prefix, field string) string {
+	switch {
+	case prefix == "":
+		return field
+	case field == "":
+		return prefix
+	case strings.HasPrefix(field, "["):
+		return prefix + field
+	default:
+		return prefix + "." + field
+	}
+}
@@ -0,0 +1,57 @@
+package fail
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidation(t *testing.T) {
+	v := NewValidation()
+	v.Check(true, "name", "required", "must not be empty")
+	v.Check(false, "age", "min", "must be at least 0")
+	addr := v.In("addresses").In("[0]")
+	addr.Add("zip", "len", "must be 7 digits")
+	addr.Addf("", "country", "%q is not supported", "xx")
+
+	err := v.Err(WithMessage("invalid user"))
+
+	wantViolations := []FieldViolation{
+		{Field: "age", Constraint: "min", Message: "must be at least 0"},
+		{Field: "addresses[0].zip", Constraint: "len", Message: "must be 7 digits"},
+		{Field: "addresses[0]", Constraint: "country", Message: `"xx" is not supported`},
+	}
+	assert.Equal(t, wantViolations, v.Violations())
+	assert.Equal(t, wantViolations, FieldViolations(err))
+
+	failErr := Unwrap(err)
+	assert.Equal(t, CodeInvalidArgument, failErr.Code)
+	assert.Equal(t, []string{"invalid user"}, failErr.Messages)
+	assert.Equal(t, "TestValidation", failErr.StackTrace[0].Func)
+	assert.Equal(t, `invalid user: invalid argument: age: must be at least 0; addresses[0].zip: must be 7 digits; addresses[0]: "xx" is not supported`, err.Error())
+
+	t.Run("no violations", func(t *testing.T) {
+		v := NewValidation()
+		v.Check(true, "name", "required", "must not be empty")
+		assert.NoError(t, v.Err())
+	})
+
+	t.Run("wrapped", func(t *testing.T) {
+		assert.Equal(t, wantViolations, FieldViolations(Wrap(err, WithMessage("outer"))))
+		assert.Nil(t, FieldViolations(New("origin")))
+		assert.Nil(t, FieldViolations(errors.New("origin")))
+	})
+
+	t.Run("json", func(t *testing.T) {
+		data, jsonErr := json.Marshal(err)
+		assert.NoError(t, jsonErr)
+		assert.Contains(t, string(data), `"violations":[{"field":"age","constraint":"min","message":"must be at least 0"},`)
+
+		var decoded Error
+		assert.NoError(t, json.Unmarshal(data, &decoded))
+		assert.Equal(t, wantViolations, FieldViolations(&decoded))
+		assert.Equal(t, err.Error(), decoded.Error())
+	})
+}